@@ -0,0 +1,51 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Las API keys tienen el formato tk_<prefijo>_<secreto>.
+// El prefijo es público y permite buscar la clave; el secreto solo se guarda como hash
+const apiKeyTag = "tk"
+
+// Generar una nueva API key. Retorna la clave completa, su prefijo y el hash a guardar
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	prefixBytes := make([]byte, 4)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	secretBytes := make([]byte, 32)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = apiKeyTag + "_" + prefix + "_" + hex.EncodeToString(secretBytes)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// Calcular el hash de una API key. Las claves tienen alta entropía, por lo que
+// SHA-256 es suficiente y permite validarlas en cada petición sin coste apreciable
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Extraer el prefijo público de una API key
+func ParseAPIKeyPrefix(key string) (string, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTag || parts[1] == "" || parts[2] == "" {
+		return "", errors.New("formato de API key inválido")
+	}
+	return parts[1], nil
+}
+
+// Comparar una API key con su hash guardado en tiempo constante
+func CompareAPIKey(key string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
@@ -0,0 +1,424 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config agrupa toda la configuración de la aplicación.
+//
+// Se carga una sola vez al arrancar con esta prioridad: variables de entorno (incluido .env),
+// archivo YAML/TOML opcional (CONFIG_FILE) y valores por defecto
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	JWT         JWTConfig         `yaml:"jwt" toml:"jwt"`
+	OIDC        OIDCConfig        `yaml:"oidc" toml:"oidc"`
+	Password    PasswordConfig    `yaml:"password" toml:"password"`
+	Workers     WorkersConfig     `yaml:"workers" toml:"workers"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+}
+
+type ServerConfig struct {
+	Port            string   `yaml:"port" toml:"port" env:"PORT"`
+	GinMode         string   `yaml:"gin_mode" toml:"gin_mode" env:"GIN_MODE"`
+	ReadTimeout     Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout    Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout     Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	// TrustedProxies son las IPs o redes (CIDR) de los proxies cuyo X-Forwarded-For se acepta
+	// como IP del cliente. Sin proxies de confianza se usa la IP de la conexión
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+type DatabaseConfig struct {
+	User     string `yaml:"user" toml:"user" env:"DB_USER"`
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD" secret:"true"`
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST"`
+	Port     string `yaml:"port" toml:"port" env:"DB_PORT"`
+	Name     string `yaml:"name" toml:"name" env:"DB_NAME"`
+
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	// QueryTimeout es el tiempo máximo que una petición puede esperar a la base de datos
+	QueryTimeout Duration `yaml:"query_timeout" toml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+
+	// Replicas son los DSN de las réplicas de solo lectura (user:pass@tcp(host:3306)/db)
+	Replicas              []string `yaml:"replicas" toml:"replicas" env:"DB_REPLICAS" secret:"true"`
+	ReplicaHealthInterval Duration `yaml:"replica_health_interval" toml:"replica_health_interval" env:"DB_REPLICA_HEALTH_INTERVAL"`
+}
+
+type JWTConfig struct {
+	Secret               string   `yaml:"secret" toml:"secret" env:"JWT_SECRET" secret:"true"`
+	PrivateKeyFile       string   `yaml:"private_key_file" toml:"private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	VerificationKeyFiles []string `yaml:"verification_key_files" toml:"verification_key_files" env:"JWT_VERIFICATION_KEY_FILES"`
+	Issuer               string   `yaml:"issuer" toml:"issuer" env:"JWT_ISSUER"`
+	Audience             string   `yaml:"audience" toml:"audience" env:"JWT_AUDIENCE"`
+}
+
+type OIDCConfig struct {
+	IssuerURL    string   `yaml:"issuer_url" toml:"issuer_url" env:"OIDC_ISSUER_URL"`
+	ClientID     string   `yaml:"client_id" toml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret" env:"OIDC_CLIENT_SECRET" secret:"true"`
+	RedirectURL  string   `yaml:"redirect_url" toml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes       []string `yaml:"scopes" toml:"scopes" env:"OIDC_SCOPES"`
+	AllowSignup  bool     `yaml:"allow_signup" toml:"allow_signup" env:"OIDC_ALLOW_SIGNUP"`
+}
+
+type PasswordConfig struct {
+	MinLength         int    `yaml:"min_length" toml:"min_length" env:"PASSWORD_MIN_LENGTH"`
+	MaxLength         int    `yaml:"max_length" toml:"max_length" env:"PASSWORD_MAX_LENGTH"`
+	RequireUpper      bool   `yaml:"require_upper" toml:"require_upper" env:"PASSWORD_REQUIRE_UPPER"`
+	RequireLower      bool   `yaml:"require_lower" toml:"require_lower" env:"PASSWORD_REQUIRE_LOWER"`
+	RequireDigit      bool   `yaml:"require_digit" toml:"require_digit" env:"PASSWORD_REQUIRE_DIGIT"`
+	RequireSymbol     bool   `yaml:"require_symbol" toml:"require_symbol" env:"PASSWORD_REQUIRE_SYMBOL"`
+	RejectCommon      bool   `yaml:"reject_common" toml:"reject_common" env:"PASSWORD_REJECT_COMMON"`
+	BlocklistFile     string `yaml:"blocklist_file" toml:"blocklist_file" env:"PASSWORD_BLOCKLIST_FILE"`
+	HashAlgorithm     string `yaml:"hash_algorithm" toml:"hash_algorithm" env:"PASSWORD_HASH_ALGORITHM"`
+	BcryptCost        int    `yaml:"bcrypt_cost" toml:"bcrypt_cost" env:"BCRYPT_COST"`
+	Argon2MemoryKB    int    `yaml:"argon2_memory_kb" toml:"argon2_memory_kb" env:"ARGON2_MEMORY_KB"`
+	Argon2Iterations  int    `yaml:"argon2_iterations" toml:"argon2_iterations" env:"ARGON2_ITERATIONS"`
+	Argon2Parallelism int    `yaml:"argon2_parallelism" toml:"argon2_parallelism" env:"ARGON2_PARALLELISM"`
+}
+
+type WorkersConfig struct {
+	SessionCleanupInterval Duration `yaml:"session_cleanup_interval" toml:"session_cleanup_interval" env:"SESSION_CLEANUP_INTERVAL"`
+	SessionRetention       Duration `yaml:"session_retention" toml:"session_retention" env:"SESSION_RETENTION"`
+	TaskMetricsInterval    Duration `yaml:"task_metrics_interval" toml:"task_metrics_interval" env:"TASK_METRICS_INTERVAL"`
+	// IdempotencyCleanupInterval es cada cuánto se eliminan las claves de idempotencia expiradas
+	IdempotencyCleanupInterval Duration `yaml:"idempotency_cleanup_interval" toml:"idempotency_cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL"`
+}
+
+type LogConfig struct {
+	// Level es el nivel mínimo: debug, info, warn o error
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL"`
+	// Format es json (para el pipeline de logs) o text (más legible en desarrollo)
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig usa las variables estándar de OpenTelemetry. El destino del exportador OTLP
+// (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_HEADERS...) lo lee directamente el exportador
+type TracingConfig struct {
+	// Exporter es otlp para enviar las trazas o none para no generarlas
+	Exporter    string `yaml:"exporter" toml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName string `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME"`
+	// SampleRatio es la fracción de trazas nuevas que se guardan (0 a 1). Si la petición
+	// llega con traceparent se respeta la decisión del servicio que llama
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+// RateLimitConfig define las cuotas de peticiones (token bucket): cada cliente puede hacer
+// hasta N peticiones seguidas y recupera N por periodo
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Registro y login, por IP
+	AuthRequests int      `yaml:"auth_requests" toml:"auth_requests" env:"RATE_LIMIT_AUTH_REQUESTS"`
+	AuthPeriod   Duration `yaml:"auth_period" toml:"auth_period" env:"RATE_LIMIT_AUTH_PERIOD"`
+	// Rutas protegidas, por usuario
+	APIRequests int      `yaml:"api_requests" toml:"api_requests" env:"RATE_LIMIT_API_REQUESTS"`
+	APIPeriod   Duration `yaml:"api_period" toml:"api_period" env:"RATE_LIMIT_API_PERIOD"`
+}
+
+type IdempotencyConfig struct {
+	// TTL es cuánto tiempo se guarda la respuesta de cada Idempotency-Key
+	TTL Duration `yaml:"ttl" toml:"ttl" env:"IDEMPOTENCY_TTL"`
+	// Lease es cuánto puede bloquear la clave una petición en curso antes de darla por abandonada
+	Lease Duration `yaml:"lease" toml:"lease" env:"IDEMPOTENCY_LEASE"`
+}
+
+// Configuración cargada al arrancar
+var App *Config
+
+// Default retorna la configuración con los valores por defecto
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			GinMode:         "debug",
+			ReadTimeout:     Duration(15 * time.Second),
+			WriteTimeout:    Duration(30 * time.Second),
+			IdleTimeout:     Duration(60 * time.Second),
+			ShutdownTimeout: Duration(20 * time.Second),
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "3306",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration(30 * time.Minute),
+			ConnMaxIdleTime: Duration(5 * time.Minute),
+			QueryTimeout:    Duration(5 * time.Second),
+
+			ReplicaHealthInterval: Duration(10 * time.Second),
+		},
+		JWT: JWTConfig{
+			Issuer:   defaultJWTIssuer,
+			Audience: defaultJWTAudience,
+		},
+		OIDC: OIDCConfig{
+			Scopes:      []string{"openid", "profile", "email"},
+			AllowSignup: true,
+		},
+		Password: defaultPasswordConfig(),
+		Workers: WorkersConfig{
+			SessionCleanupInterval: Duration(time.Hour),
+			SessionRetention:       Duration(7 * 24 * time.Hour),
+			TaskMetricsInterval:    Duration(30 * time.Second),
+
+			IdempotencyCleanupInterval: Duration(time.Hour),
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "go-task-api",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:      true,
+			AuthRequests: 10,
+			AuthPeriod:   Duration(time.Minute),
+			APIRequests:  300,
+			APIPeriod:    Duration(time.Minute),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:   Duration(24 * time.Hour),
+			Lease: Duration(time.Minute),
+		},
+	}
+}
+
+// Load carga .env, el archivo de configuración (file o CONFIG_FILE) y las variables de
+// entorno, valida el resultado y lo guarda en App
+func Load(file string) (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		slog.Warn("No se encontró el archivo .env")
+	}
+
+	cfg := Default()
+
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		if err := cfg.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	App = cfg
+	return cfg, nil
+}
+
+// Validate comprueba la configuración completa y reporta todos los errores a la vez
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("PORT inválido: %q", c.Server.Port))
+	}
+	if c.Server.GinMode != "debug" && c.Server.GinMode != "release" && c.Server.GinMode != "test" {
+		errs = append(errs, fmt.Errorf("GIN_MODE inválido: %q (use debug, release o test)", c.Server.GinMode))
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.IdleTimeout <= 0 || c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SERVER_READ_TIMEOUT, SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT y SERVER_SHUTDOWN_TIMEOUT deben ser positivos"))
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %q no es una IP ni una red CIDR", proxy))
+		}
+	}
+	if c.Database.User == "" || c.Database.Host == "" || c.Database.Name == "" {
+		errs = append(errs, errors.New("DB_USER, DB_HOST y DB_NAME son obligatorios"))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 || c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME y DB_CONN_MAX_IDLE_TIME no pueden ser negativos (0 = sin límite)"))
+	}
+	if c.Database.QueryTimeout <= 0 {
+		errs = append(errs, errors.New("DB_QUERY_TIMEOUT debe ser positivo"))
+	}
+	if len(c.Database.Replicas) > 0 && c.Database.ReplicaHealthInterval <= 0 {
+		errs = append(errs, errors.New("DB_REPLICA_HEALTH_INTERVAL debe ser positivo"))
+	}
+	if c.JWT.Secret == "" && c.JWT.PrivateKeyFile == "" {
+		errs = append(errs, errors.New("JWT_SECRET no configurado (o JWT_PRIVATE_KEY_FILE)"))
+	}
+	if c.OIDC.IssuerURL != "" && (c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "") {
+		errs = append(errs, errors.New("OIDC_CLIENT_ID y OIDC_REDIRECT_URL son obligatorios si OIDC_ISSUER_URL está definido"))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL inválido: %q (use debug, info, warn o error)", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT inválido: %q (use json o text)", c.Log.Format))
+	}
+	if c.Tracing.Exporter != "otlp" && c.Tracing.Exporter != "none" {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_EXPORTER inválido: %q (use otlp o none)", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("OTEL_TRACES_SAMPLER_ARG debe estar entre 0 y 1"))
+	}
+	if c.RateLimit.Enabled && (c.RateLimit.AuthRequests <= 0 || c.RateLimit.AuthPeriod <= 0 || c.RateLimit.APIRequests <= 0 || c.RateLimit.APIPeriod <= 0) {
+		errs = append(errs, errors.New("RATE_LIMIT_AUTH_REQUESTS, RATE_LIMIT_AUTH_PERIOD, RATE_LIMIT_API_REQUESTS y RATE_LIMIT_API_PERIOD deben ser positivos"))
+	}
+	if c.Idempotency.TTL <= 0 || c.Idempotency.Lease <= 0 || c.Workers.IdempotencyCleanupInterval <= 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_TTL, IDEMPOTENCY_LEASE e IDEMPOTENCY_CLEANUP_INTERVAL deben ser positivos"))
+	}
+	if err := c.Password.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Workers.SessionCleanupInterval <= 0 || c.Workers.SessionRetention < 0 {
+		errs = append(errs, errors.New("SESSION_CLEANUP_INTERVAL debe ser positivo y SESSION_RETENTION no puede ser negativo"))
+	}
+	if c.Workers.TaskMetricsInterval <= 0 {
+		errs = append(errs, errors.New("TASK_METRICS_INTERVAL debe ser positivo"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted retorna una copia de la configuración con los secretos ocultos, apta para logs y diagnóstico
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redact(reflect.ValueOf(&redacted).Elem())
+	return &redacted
+}
+
+// String serializa la configuración redactada en YAML
+func (c *Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// loadFile aplica un archivo YAML o TOML sobre la configuración actual
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("no se pudo leer el archivo de configuración: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("formato de configuración no soportado: %s (use .yaml, .yml o .toml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("archivo de configuración inválido %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv recorre la configuración y sobrescribe cada campo con su variable de entorno (tag env)
+func applyEnv(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := v.Type().Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := structField.Tag.Get("env")
+		value, ok := os.LookupEnv(name)
+		if name == "" || !ok || value == "" {
+			continue
+		}
+
+		// Tipos con formato propio, como Duration
+		if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("%s inválido: %w", name, err)
+			}
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s debe ser un número: %w", name, err)
+			}
+			field.SetInt(n)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s debe ser un número: %w", name, err)
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s debe ser true o false: %w", name, err)
+			}
+			field.SetBool(b)
+		case reflect.Slice:
+			// Listas separadas por comas o espacios
+			items := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+			field.Set(reflect.ValueOf(items))
+		}
+	}
+	return nil
+}
+
+// redact reemplaza los campos marcados con secret:"true" que tengan valor
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			redact(field)
+			continue
+		}
+		if v.Type().Field(i).Tag.Get("secret") != "true" {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Slice:
+			redacted := make([]string, field.Len())
+			for j := range redacted {
+				redacted[j] = "[REDACTED]"
+			}
+			field.Set(reflect.ValueOf(redacted))
+		case field.String() != "":
+			field.SetString("[REDACTED]")
+		}
+	}
+}
@@ -1,37 +1,85 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func ConnectDB() {
-	err := godotenv.Load()
+// ConnectDB abre la conexión principal y las réplicas, registrando en todas los plugins indicados
+func ConnectDB(cfg DatabaseConfig, plugins ...gorm.Plugin) error {
+	// DSN mejorado con charset y parseTime
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+
+	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
 	if err != nil {
-		log.Println("Advertencia: No se encontró el archivo .env")
+		return fmt.Errorf("error al conectar con la base de datos: %w", err)
 	}
 
-	user := os.Getenv("DB_USER")
-	pass := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	name := os.Getenv("DB_NAME")
-
-	// DSN mejorado con charset y parseTime
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", user, pass, host, port, name)
+	if err := configurePool(database, cfg); err != nil {
+		return err
+	}
+	if err := usePlugins(database, plugins); err != nil {
+		return err
+	}
 
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	replicas, err := ConnectReplicas(cfg, plugins...)
 	if err != nil {
-		log.Fatal("Error al conectar con la base de datos:", err)
+		return err
 	}
 
 	DB = database
-	log.Println("Conexión exitosa a la base de datos")
+	Replicas = replicas
+	return nil
+}
+
+// configurePool aplica los límites del pool de conexiones
+func configurePool(database *gorm.DB, cfg DatabaseConfig) error {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime.Std())
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Std())
+	return nil
+}
+
+func usePlugins(database *gorm.DB, plugins []gorm.Plugin) error {
+	for _, plugin := range plugins {
+		if err := database.Use(plugin); err != nil {
+			return fmt.Errorf("error al registrar el plugin %s: %w", plugin.Name(), err)
+		}
+	}
+	return nil
+}
+
+// CloseDB cierra el pool de conexiones de la base de datos y de sus réplicas
+func CloseDB() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return errors.Join(sqlDB.Close(), Replicas.Close())
+}
+
+// PingDB comprueba que la base de datos responde
+func PingDB(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("base de datos no conectada")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// Duration es un time.Duration que se lee como texto ("15s", "1m30s") desde YAML, TOML
+// y variables de entorno
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Std retorna el valor como time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
@@ -1,51 +1,264 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Duración de los tokens (y de las sesiones que representan)
+const TokenTTL = 24 * time.Hour
+
+const (
+	defaultJWTIssuer   = "go-task-api"
+	defaultJWTAudience = "go-task-api"
+	// Margen para diferencias de reloj entre servicios al validar exp/nbf/iat
+	tokenLeeway = 30 * time.Second
+	hmacKeyID   = "hmac"
+)
+
 type Claims struct {
-	Username string `json:"username"`
+	Username  string `json:"username"`
+	SessionID uint   `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// Generar token JWT
-func GenerateToken(username string) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", errors.New("JWT_SECRET no configurado")
+// jwtKey es una clave de firma o verificación identificada por su kid
+type jwtKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{} // nil si la clave solo sirve para verificar
+	Public  interface{}
+}
+
+// JWTKeySet contiene la clave activa de firma y todas las claves aceptadas al verificar
+type JWTKeySet struct {
+	Issuer       string
+	Audience     string
+	signing      *jwtKey
+	verification map[string]*jwtKey
+}
+
+var keySet *JWTKeySet
+
+// LoadJWTKeys carga las claves una sola vez al arrancar.
+//
+// Con PrivateKeyFile (PEM RSA o Ed25519) se firma con RS256/EdDSA; las claves anteriores
+// que aún deben aceptarse durante una rotación van en VerificationKeyFiles. Sin clave
+// privada se usa HS256 con Secret, que también se acepta para verificar durante la
+// migración a claves asimétricas
+func LoadJWTKeys(cfg JWTConfig) error {
+	set := &JWTKeySet{
+		Issuer:       cfg.Issuer,
+		Audience:     cfg.Audience,
+		verification: make(map[string]*jwtKey),
+	}
+
+	if secret := cfg.Secret; secret != "" {
+		hmacKey := &jwtKey{ID: hmacKeyID, Method: jwt.SigningMethodHS256, Private: []byte(secret), Public: []byte(secret)}
+		set.signing = hmacKey
+		set.verification[hmacKey.ID] = hmacKey
+	}
+
+	if path := cfg.PrivateKeyFile; path != "" {
+		key, err := loadPEMKey(path)
+		if err != nil {
+			return err
+		}
+		if key.Private == nil {
+			return fmt.Errorf("%s no contiene una clave privada", path)
+		}
+		set.signing = key
+		set.verification[key.ID] = key
+	}
+
+	for _, path := range cfg.VerificationKeyFiles {
+		key, err := loadPEMKey(path)
+		if err != nil {
+			return err
+		}
+		set.verification[key.ID] = key
+	}
+
+	if set.signing == nil {
+		return errors.New("JWT_SECRET no configurado")
+	}
+
+	keySet = set
+	return nil
+}
+
+// Generar token JWT asociado a una sesión
+func GenerateToken(username string, sessionID uint) (string, error) {
+	if keySet == nil {
+		return "", errors.New("claves JWT no cargadas")
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
+	now := time.Now()
 	claims := &Claims{
-		Username: username,
+		Username:  username,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			Issuer:    keySet.Issuer,
+			Subject:   username,
+			Audience:  jwt.ClaimStrings{keySet.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(TokenTTL)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(keySet.signing.Method, claims)
+	token.Header["kid"] = keySet.signing.ID
+	return token.SignedString(keySet.signing.Private)
 }
 
 // Validar token
 func ValidateToken(tokenString string) (*Claims, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("JWT_SECRET no configurado")
+	if keySet == nil {
+		return nil, errors.New("claves JWT no cargadas")
 	}
 
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keySet.verification[kid]
+		if !ok {
+			return nil, fmt.Errorf("kid desconocido: %q", kid)
+		}
+		// El algoritmo lo decide la clave, no la cabecera del token
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("algoritmo %s no válido para la clave %s", token.Method.Alg(), kid)
+		}
+		return key.Public, nil
+	},
+		jwt.WithValidMethods(keySet.validMethods()),
+		jwt.WithIssuer(keySet.Issuer),
+		jwt.WithAudience(keySet.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(tokenLeeway),
+	)
 
 	if err != nil || !token.Valid {
 		return nil, err
 	}
 	return claims, nil
 }
+
+// PublicJWKS retorna las claves públicas de verificación en formato JWK Set.
+// Las claves HMAC son secretas y nunca se publican
+func PublicJWKS() map[string]interface{} {
+	keys := []map[string]string{}
+	if keySet != nil {
+		for _, key := range keySet.verification {
+			if jwk := key.jwk(); jwk != nil {
+				keys = append(keys, jwk)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i]["kid"] < keys[j]["kid"] })
+	return map[string]interface{}{"keys": keys}
+}
+
+func (s *JWTKeySet) validMethods() []string {
+	seen := make(map[string]bool)
+	methods := []string{}
+	for _, key := range s.verification {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+func (k *jwtKey) jwk() map[string]string {
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": k.Method.Alg(),
+			"kid": k.ID,
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"use": "sig",
+			"alg": k.Method.Alg(),
+			"kid": k.ID,
+			"x":   base64.RawURLEncoding.EncodeToString(public),
+		}
+	}
+	return nil
+}
+
+// loadPEMKey lee una clave privada (PKCS#8 o PKCS#1) o pública (PKIX) desde un archivo PEM.
+// El kid se deriva de la clave pública, así es estable entre reinicios y réplicas
+func loadPEMKey(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la clave %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s no contiene un bloque PEM", path)
+	}
+
+	var private, public interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("tipo de bloque PEM no soportado: %s", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("clave inválida en %s: %w", path, err)
+	}
+
+	key := &jwtKey{Private: private}
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	case ed25519.PrivateKey:
+		public = k.Public()
+	}
+
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("%s: solo se soportan claves RSA y Ed25519", path)
+	}
+	key.Public = public
+
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	key.ID = hex.EncodeToString(sum[:8])
+	return key, nil
+}
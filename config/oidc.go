@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"errors"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProvider agrupa la configuración del proveedor de identidad (IdP)
+type OIDCProvider struct {
+	Issuer      string
+	OAuth2      oauth2.Config
+	Verifier    *oidc.IDTokenVerifier
+	AllowSignup bool // crear usuarios nuevos si no existe ninguno vinculado
+}
+
+// OIDC es nil mientras el login con el proveedor de identidad no esté configurado
+var OIDC *OIDCProvider
+
+// Enabled indica si el login con proveedor de identidad está configurado
+func (cfg OIDCConfig) Enabled() bool {
+	return cfg.IssuerURL != ""
+}
+
+// InitOIDC realiza el discovery del proveedor y prepara el cliente OAuth2
+func InitOIDC(ctx context.Context, cfg OIDCConfig) error {
+	if cfg.IssuerURL == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return errors.New("OIDC_ISSUER_URL, OIDC_CLIENT_ID y OIDC_REDIRECT_URL son obligatorios")
+	}
+
+	// El discovery obtiene los endpoints y el JWKS a partir de /.well-known/openid-configuration
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return err
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+
+	OIDC = &OIDCProvider{
+		Issuer: cfg.IssuerURL,
+		OAuth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		// El verificador valida firma (JWKS), issuer, audiencia y expiración del id_token
+		Verifier:    provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		AllowSignup: cfg.AllowSignup,
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"go-task-manager-mvc/i18n"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algoritmos de hash de contraseñas soportados
+const (
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashBcrypt   = "bcrypt"
+)
+
+// bcrypt ignora lo que pase de 72 bytes, así que con bcrypt se rechazan contraseñas más largas
+const bcryptMaxBytes = 72
+
+//go:embed common_passwords.txt
+var bundledCommonPasswords string
+
+// PasswordPolicy define los requisitos que debe cumplir una contraseña nueva
+type PasswordPolicy struct {
+	MinLength     int  `json:"min_length"`
+	MaxLength     int  `json:"max_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	RejectCommon  bool `json:"reject_common"`
+	// MaxBytes es el límite en bytes del algoritmo de hash (solo bcrypt; 0 sin límite)
+	MaxBytes   int `json:"max_bytes,omitempty"`
+	commonList map[string]bool
+}
+
+// PasswordHashing define el algoritmo y coste con los que se guardan las contraseñas.
+// Los hashes con otro algoritmo o coste se actualizan al iniciar sesión
+type PasswordHashing struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      uint32 // KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+// Configuración vigente; LoadPasswordSettings la reemplaza al arrancar
+var (
+	PasswordRules, _ = newPasswordPolicy(defaultPasswordConfig())
+	PasswordHashers  = newPasswordHashing(defaultPasswordConfig())
+)
+
+func defaultPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		MinLength:         6,
+		MaxLength:         128,
+		RejectCommon:      true,
+		HashAlgorithm:     PasswordHashArgon2id,
+		BcryptCost:        bcrypt.DefaultCost,
+		Argon2MemoryKB:    64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+	}
+}
+
+// validate comprueba que los parámetros de contraseñas sean coherentes
+func (cfg PasswordConfig) validate() error {
+	if cfg.MinLength < 1 || cfg.MaxLength < cfg.MinLength {
+		return errors.New("PASSWORD_MIN_LENGTH/PASSWORD_MAX_LENGTH inválidos")
+	}
+	if cfg.HashAlgorithm != PasswordHashArgon2id && cfg.HashAlgorithm != PasswordHashBcrypt {
+		return fmt.Errorf("PASSWORD_HASH_ALGORITHM inválido: %s", cfg.HashAlgorithm)
+	}
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		return fmt.Errorf("BCRYPT_COST debe estar entre %d y %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if cfg.Argon2MemoryKB < 8 || cfg.Argon2Iterations < 1 || cfg.Argon2Parallelism < 1 || cfg.Argon2Parallelism > 255 {
+		return errors.New("parámetros de argon2id inválidos")
+	}
+	return nil
+}
+
+// LoadPasswordSettings aplica la política y el algoritmo de hash configurados
+func LoadPasswordSettings(cfg PasswordConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	policy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		return err
+	}
+	PasswordRules = policy
+	PasswordHashers = newPasswordHashing(cfg)
+	return nil
+}
+
+func newPasswordPolicy(cfg PasswordConfig) (PasswordPolicy, error) {
+	policy := PasswordPolicy{
+		MinLength:     cfg.MinLength,
+		MaxLength:     cfg.MaxLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+		RejectCommon:  cfg.RejectCommon,
+		commonList:    parsePasswordList(bundledCommonPasswords),
+	}
+	if cfg.HashAlgorithm == PasswordHashBcrypt {
+		policy.MaxBytes = bcryptMaxBytes
+	}
+
+	if cfg.BlocklistFile != "" {
+		data, err := os.ReadFile(cfg.BlocklistFile)
+		if err != nil {
+			return policy, fmt.Errorf("no se pudo leer PASSWORD_BLOCKLIST_FILE: %w", err)
+		}
+		for password := range parsePasswordList(string(data)) {
+			policy.commonList[password] = true
+		}
+	}
+	return policy, nil
+}
+
+func newPasswordHashing(cfg PasswordConfig) PasswordHashing {
+	return PasswordHashing{
+		Algorithm:         cfg.HashAlgorithm,
+		BcryptCost:        cfg.BcryptCost,
+		Argon2Memory:      uint32(cfg.Argon2MemoryKB),
+		Argon2Iterations:  uint32(cfg.Argon2Iterations),
+		Argon2Parallelism: uint8(cfg.Argon2Parallelism),
+	}
+}
+
+// Validate retorna la lista de requisitos que la contraseña no cumple
+func (p PasswordPolicy) Validate(password, username, email string) []i18n.Message {
+	var violations []i18n.Message
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		violations = append(violations, i18n.Msg("password.min_length", p.MinLength))
+	}
+	if length > p.MaxLength {
+		violations = append(violations, i18n.Msg("password.max_length", p.MaxLength))
+	}
+	if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		violations = append(violations, i18n.Msg("password.max_bytes", p.MaxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, i18n.Msg("password.upper"))
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, i18n.Msg("password.lower"))
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, i18n.Msg("password.digit"))
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, i18n.Msg("password.symbol"))
+	}
+
+	lower := strings.ToLower(password)
+	if p.RejectCommon && p.commonList[lower] {
+		violations = append(violations, i18n.Msg("password.common"))
+	}
+
+	localPart := strings.Split(strings.ToLower(email), "@")[0]
+	for _, personal := range []string{strings.ToLower(username), localPart} {
+		if len(personal) >= 3 && strings.Contains(lower, personal) {
+			violations = append(violations, i18n.Msg("password.personal"))
+			break
+		}
+	}
+
+	return violations
+}
+
+// HashPassword genera el hash de una contraseña con el algoritmo configurado
+func HashPassword(password string) (string, error) {
+	h := PasswordHashers
+	if h.Algorithm == PasswordHashBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Argon2Iterations, h.Argon2Memory, h.Argon2Parallelism, 32)
+
+	// Formato PHC: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Argon2Memory, h.Argon2Iterations, h.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifyPassword compara una contraseña con su hash (argon2id o bcrypt).
+// needsRehash indica que el hash usa un algoritmo o coste distinto del configurado
+func VerifyPassword(password, hash string) (ok bool, needsRehash bool) {
+	h := PasswordHashers
+
+	if strings.HasPrefix(hash, "$argon2id$") {
+		params, salt, key, err := decodeArgon2Hash(hash)
+		if err != nil {
+			return false, false
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(computed, key) != 1 {
+			return false, false
+		}
+		return true, h.Algorithm != PasswordHashArgon2id ||
+			params.Argon2Memory != h.Argon2Memory ||
+			params.Argon2Iterations != h.Argon2Iterations ||
+			params.Argon2Parallelism != h.Argon2Parallelism
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err != nil || h.Algorithm != PasswordHashBcrypt || cost != h.BcryptCost
+}
+
+// decodeArgon2Hash extrae parámetros, salt y clave de un hash argon2id en formato PHC
+func decodeArgon2Hash(hash string) (PasswordHashing, []byte, []byte, error) {
+	var params PasswordHashing
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("hash argon2id inválido")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("versión de argon2 no soportada")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Iterations, &params.Argon2Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	return params, salt, key, nil
+}
+
+// parsePasswordList convierte un archivo (una contraseña por línea, # para comentarios) en un set
+func parsePasswordList(data string) map[string]bool {
+	list := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			list[strings.ToLower(line)] = true
+		}
+	}
+	return list
+}
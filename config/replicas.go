@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// Tiempo máximo de cada comprobación de salud de una réplica
+const replicaPingTimeout = 2 * time.Second
+
+// Réplicas de lectura configuradas (nil si no hay ninguna)
+var Replicas *ReplicaSet
+
+// ReplicaSet reparte las lecturas entre las réplicas que están respondiendo
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	addr    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// ConnectReplicas abre las réplicas de cfg.Replicas y comprueba cuáles responden.
+// Una réplica caída no impide arrancar: sus lecturas van al primario hasta que se recupere
+func ConnectReplicas(cfg DatabaseConfig, plugins ...gorm.Plugin) (*ReplicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+
+	set := &ReplicaSet{}
+	for i, dsn := range cfg.Replicas {
+		dsnConfig, err := replicaDSN(dsn)
+		if err != nil {
+			set.Close()
+			return nil, fmt.Errorf("DSN de la réplica %d inválido: %w", i+1, err)
+		}
+
+		// Sin ping ni consulta de versión al abrir: la réplica puede estar caída
+		database, err := gorm.Open(mysql.New(mysql.Config{
+			DSN:                       dsnConfig.FormatDSN(),
+			SkipInitializeWithVersion: true,
+		}), &gorm.Config{DisableAutomaticPing: true})
+		if err == nil {
+			err = configurePool(database, cfg)
+		}
+		if err == nil {
+			err = usePlugins(database, plugins)
+		}
+		if err != nil {
+			set.Close()
+			return nil, fmt.Errorf("error al abrir la réplica %s: %w", dsnConfig.Addr, err)
+		}
+		set.replicas = append(set.replicas, &replica{addr: dsnConfig.Addr, db: database})
+	}
+
+	if err := set.CheckHealth(context.Background()); err != nil {
+		slog.Warn("Hay réplicas de lectura que no responden", "error", err)
+	}
+	slog.Info("Réplicas de lectura configuradas", "replicas", len(set.replicas))
+	return set, nil
+}
+
+// replicaDSN aplica a un DSN de réplica las mismas opciones que usa la conexión principal
+func replicaDSN(dsn string) (*mysqldriver.Config, error) {
+	dsnConfig, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	dsnConfig.ParseTime = true
+	dsnConfig.Loc = time.Local
+	if !strings.Contains(dsn, "charset=") {
+		if err := dsnConfig.Apply(mysqldriver.Charset("utf8mb4", "")); err != nil {
+			return nil, err
+		}
+	}
+	return dsnConfig, nil
+}
+
+// Replica retorna una réplica sana, rotando entre ellas, o nil si no hay ninguna disponible
+func (s *ReplicaSet) Replica() *gorm.DB {
+	if s == nil || len(s.replicas) == 0 {
+		return nil
+	}
+
+	start := s.next.Add(1)
+	count := uint64(len(s.replicas))
+	for i := uint64(0); i < count; i++ {
+		r := s.replicas[(start+i)%count]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+// MarkUnhealthy deja de usar una réplica hasta que vuelva a pasar la comprobación de salud
+func (s *ReplicaSet) MarkUnhealthy(database *gorm.DB) {
+	if s == nil {
+		return
+	}
+	for _, r := range s.replicas {
+		if r.db == database && r.healthy.Swap(false) {
+			slog.Warn("Réplica no disponible: las lecturas van al primario", "replica", r.addr)
+		}
+	}
+}
+
+// CheckHealth comprueba cada réplica y actualiza su estado.
+// Retorna un error con las réplicas que no responden
+func (s *ReplicaSet) CheckHealth(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range s.replicas {
+		err := r.ping(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("réplica %s no disponible: %w", r.addr, err))
+		}
+		if wasHealthy := r.healthy.Swap(err == nil); err == nil && !wasHealthy {
+			slog.Info("Réplica disponible", "replica", r.addr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *replica) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// SQLDBs retorna el pool de conexiones de cada réplica por su dirección (host:puerto)
+func (s *ReplicaSet) SQLDBs() map[string]*sql.DB {
+	if s == nil {
+		return nil
+	}
+
+	pools := make(map[string]*sql.DB, len(s.replicas))
+	for _, r := range s.replicas {
+		if sqlDB, err := r.db.DB(); err == nil {
+			pools[r.addr] = sqlDB
+		}
+	}
+	return pools
+}
+
+// Close cierra las conexiones de todas las réplicas
+func (s *ReplicaSet) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range s.replicas {
+		if sqlDB, err := r.db.DB(); err == nil {
+			errs = append(errs, sqlDB.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
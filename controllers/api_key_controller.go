@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController expone la gestión de API keys del usuario autenticado
+type APIKeyController struct {
+	apiKeys *services.APIKeyService
+}
+
+func NewAPIKeyController(apiKeys *services.APIKeyService) *APIKeyController {
+	return &APIKeyController{apiKeys: apiKeys}
+}
+
+// CreateAPIKey crea una API key para el usuario autenticado.
+// La clave completa solo se devuelve en esta respuesta
+func (ac *APIKeyController) CreateAPIKey(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	var request models.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Respond(c, problem.Binding(err).With("valid_scopes", models.GrantableAPIKeyScopes()))
+		return
+	}
+
+	key, apiKey, err := ac.apiKeys.Create(c.Request.Context(), userID, request)
+	if errors.Is(err, services.ErrAPIKeyExpiresInPast) {
+		respondValidationErrors(c, models.ValidationErrors{
+			{Field: "expires_at", Code: "past", Message: i18n.Msg("validation.past")},
+		})
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.api_key.create_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": localize(c, "message.api_key.created"),
+		"key":     key,
+		"api_key": apiKey.ToResponse(),
+	})
+}
+
+// GetAPIKeys devuelve las API keys del usuario autenticado
+func (ac *APIKeyController) GetAPIKeys(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	apiKeys, err := ac.apiKeys.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.api_key.list_failed")
+		return
+	}
+
+	responses := make([]models.APIKeyResponse, len(apiKeys))
+	for i := range apiKeys {
+		responses[i] = apiKeys[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": responses,
+		"count":    len(responses),
+	})
+}
+
+// RevokeAPIKey revoca una API key del usuario autenticado
+func (ac *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeAPIKeyNotFound, "error.api_key.not_found")
+		return
+	}
+
+	apiKey, err := ac.apiKeys.Revoke(c.Request.Context(), userID, id)
+	if errors.Is(err, services.ErrAPIKeyNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeAPIKeyNotFound, "error.api_key.not_found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.api_key.revoke_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.api_key.revoked"),
+		"api_key": apiKey.ToResponse(),
+	})
+}
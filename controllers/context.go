@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserIDFromContext retorna el ID del usuario autenticado (lo guarda AuthMiddleware)
+func getUserIDFromContext(c *gin.Context) (uint, error) {
+	userID, _ := c.Get("user_id")
+	id, ok := userID.(uint)
+	if !ok || id == 0 {
+		return 0, errors.New("usuario no autenticado")
+	}
+	return id, nil
+}
+
+// getSessionIDFromContext retorna el ID de la sesión de la petición actual
+func getSessionIDFromContext(c *gin.Context) uint {
+	sessionID, _ := c.Get("session_id")
+	id, _ := sessionID.(uint)
+	return id
+}
+
+// parseIDParam lee un parámetro de ruta numérico
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// clientInfo extrae los datos del cliente que se guardan con cada sesión
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocale retorna el idioma de la petición
+func requestLocale(c *gin.Context) i18n.Locale {
+	return i18n.FromContext(c.Request.Context())
+}
+
+// localize traduce un mensaje del catálogo al idioma de la petición
+func localize(c *gin.Context, key string, args ...any) string {
+	return i18n.T(requestLocale(c), key, args...)
+}
+
+// respondError responde el error en formato problem+json con el código indicado.
+// key es la clave del detalle en el catálogo de mensajes
+func respondError(c *gin.Context, status int, code problem.Code, key string, args ...any) {
+	problem.Respond(c, problem.New(status, code, key, args...))
+}
+
+// NotFound responde a las rutas que no existen con el mismo formato que el resto de errores
+func NotFound(c *gin.Context) {
+	respondError(c, http.StatusNotFound, problem.CodeRouteNotFound, "error.route_not_found", c.Request.URL.Path)
+}
+
+// respondUnauthenticated responde a las peticiones sin usuario en el contexto
+func respondUnauthenticated(c *gin.Context) {
+	respondError(c, http.StatusUnauthorized, problem.CodeUnauthenticated, "error.unauthenticated")
+}
+
+// respondInternalError responde 503/504 si la base de datos no está disponible
+// o no respondió a tiempo, y 500 con el mensaje indicado en el resto de casos.
+// El error se registra en el log de acceso de la petición y nunca se envía al cliente
+func respondInternalError(c *gin.Context, err error, key string) {
+	c.Error(err)
+	if respondUnavailable(c, err) {
+		return
+	}
+	respondError(c, http.StatusInternalServerError, problem.CodeInternal, key)
+}
+
+// respondUnavailable responde a los errores de disponibilidad de la base de datos.
+// Retorna false si err no es de ese tipo
+func respondUnavailable(c *gin.Context, err error) bool {
+	p := problem.Unavailable(err)
+	if p == nil {
+		return false
+	}
+	problem.Respond(c, p)
+	return true
+}
+
+// respondValidationErrors responde 400 con los campos inválidos de una entidad
+func respondValidationErrors(c *gin.Context, errs models.ValidationErrors) {
+	fields := make([]problem.FieldError, len(errs))
+	for i, fieldErr := range errs {
+		fields[i] = problem.Field(fieldErr.Field, fieldErr.Code, fieldErr.Message)
+	}
+	problem.Respond(c, problem.Validation(fields...))
+}
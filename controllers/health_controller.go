@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-task-manager-mvc/buildinfo"
+	"go-task-manager-mvc/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tiempo máximo para ejecutar todas las comprobaciones de /readyz
+const readinessTimeout = 2 * time.Second
+
+// Códigos estables de una comprobación fallida. El detalle del error solo va al log:
+// /readyz no requiere autenticación y no debe mostrar direcciones ni mensajes internos
+const (
+	readinessCodeTimeout = "timeout"
+	readinessCodeFailed  = "failed"
+)
+
+// ReadinessCheck es una comprobación de la que depende que la API pueda atender peticiones
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthController expone las sondas para el orquestador
+type HealthController struct {
+	checks []ReadinessCheck
+}
+
+func NewHealthController(checks ...ReadinessCheck) *HealthController {
+	return &HealthController{checks: checks}
+}
+
+// Healthz indica que el proceso está vivo. No consulta dependencias externas
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz ejecuta todas las comprobaciones y responde 503 si alguna falla
+func (hc *HealthController) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	ready := true
+	results := make(gin.H, len(hc.checks))
+	for _, check := range hc.checks {
+		start := time.Now()
+		err := check.Check(ctx)
+
+		result := gin.H{
+			"status":      "ok",
+			"duration_ms": time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			ready = false
+			result["status"] = "error"
+			result["code"] = readinessCode(err)
+			logging.FromContext(ctx).Warn("Comprobación de disponibilidad fallida", "check", check.Name, "error", err)
+		}
+		results[check.Name] = result
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": results,
+	})
+}
+
+// readinessCode clasifica el error de una comprobación
+func readinessCode(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return readinessCodeTimeout
+	}
+	return readinessCodeFailed
+}
+
+// Version devuelve la información de compilación del binario
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-task-manager-mvc/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS publica las claves públicas con las que se pueden verificar nuestros tokens
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, config.PublicJWKS())
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+// oidcLoginState guarda los datos de un login en curso hasta que vuelve el callback
+type oidcLoginState struct {
+	nonce     string
+	verifier  string
+	expiresAt time.Time
+}
+
+// oidcStateStore guarda los logins pendientes indexados por state.
+// Cada state solo puede usarse una vez
+type oidcStateStore struct {
+	sync.Mutex
+	pending map[string]oidcLoginState
+}
+
+// oidcClaims son los claims del id_token que usamos para vincular o crear usuarios
+type oidcClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// OIDCController expone el login a través de un proveedor de identidad externo.
+// provider es nil si OIDC no está configurado
+type OIDCController struct {
+	auth     *services.AuthService
+	provider *config.OIDCProvider
+	states   *oidcStateStore
+}
+
+func NewOIDCController(auth *services.AuthService, provider *config.OIDCProvider) *OIDCController {
+	return &OIDCController{
+		auth:     auth,
+		provider: provider,
+		states:   &oidcStateStore{pending: make(map[string]oidcLoginState)},
+	}
+}
+
+// OIDCLogin inicia el flujo authorization code + PKCE redirigiendo al proveedor de identidad
+func (oc *OIDCController) OIDCLogin(c *gin.Context) {
+	if oc.provider == nil {
+		respondError(c, http.StatusNotFound, problem.CodeOIDCDisabled, "error.oidc.disabled")
+		return
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, problem.CodeInternal, "error.oidc.start_failed")
+		return
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, problem.CodeInternal, "error.oidc.start_failed")
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	oc.states.save(state, oidcLoginState{
+		nonce:     nonce,
+		verifier:  verifier,
+		expiresAt: time.Now().Add(oidcStateTTL),
+	})
+
+	// La cookie ata el state al navegador que inició el login (protección CSRF)
+	secure := strings.HasPrefix(oc.provider.OAuth2.RedirectURL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, int(oidcStateTTL.Seconds()), "/api/auth/oidc", "", secure, true)
+
+	authURL := oc.provider.OAuth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback recibe el código del proveedor, valida el id_token y emite nuestro JWT
+func (oc *OIDCController) OIDCCallback(c *gin.Context) {
+	if oc.provider == nil {
+		respondError(c, http.StatusNotFound, problem.CodeOIDCDisabled, "error.oidc.disabled")
+		return
+	}
+
+	if idpError := c.Query("error"); idpError != "" {
+		problem.Respond(c, problem.New(http.StatusUnauthorized, problem.CodeOIDCRejected,
+			"error.oidc.rejected").With("reason", idpError))
+		return
+	}
+
+	state := c.Query("state")
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	pending, ok := oc.states.consume(state)
+	if state == "" || state != cookieState || !ok {
+		respondError(c, http.StatusBadRequest, problem.CodeOIDCInvalidState, "error.oidc.invalid_state")
+		return
+	}
+	c.SetCookie(oidcStateCookie, "", -1, "/api/auth/oidc", "", false, true)
+
+	code := c.Query("code")
+	if code == "" {
+		respondError(c, http.StatusBadRequest, problem.CodeOIDCMissingCode, "error.oidc.missing_code")
+		return
+	}
+
+	// Canjear el código enviando el code_verifier de PKCE
+	ctx := c.Request.Context()
+	oauthToken, err := oc.provider.OAuth2.Exchange(ctx, code, oauth2.VerifierOption(pending.verifier))
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, problem.CodeOIDCRejected, "error.oidc.exchange_failed")
+		return
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, problem.CodeOIDCInvalidToken, "error.oidc.missing_id_token")
+		return
+	}
+
+	idToken, err := oc.provider.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, problem.CodeOIDCInvalidToken, "error.oidc.invalid_id_token")
+		return
+	}
+	if idToken.Nonce != pending.nonce {
+		respondError(c, http.StatusUnauthorized, problem.CodeOIDCInvalidToken, "error.oidc.nonce_mismatch")
+		return
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		respondError(c, http.StatusUnauthorized, problem.CodeOIDCInvalidToken, "error.oidc.invalid_id_token")
+		return
+	}
+
+	identity := services.ExternalIdentity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+	}
+	token, user, err := oc.auth.LoginWithIdentity(c.Request.Context(), identity, oc.provider.AllowSignup, clientInfo(c))
+	if errors.Is(err, services.ErrSignupDisabled) {
+		respondError(c, http.StatusForbidden, problem.CodeOIDCUserNotLinked, "error.oidc.user_not_linked")
+		return
+	}
+	if errors.Is(err, services.ErrEmailTaken) {
+		respondError(c, http.StatusConflict, problem.CodeEmailTaken, "error.oidc.email_taken")
+		return
+	}
+	if errors.Is(err, services.ErrUsernameTaken) {
+		respondError(c, http.StatusConflict, problem.CodeUsernameTaken, "error.user.username_taken")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.oidc.link_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.auth.login"),
+		"token":   token,
+		"user":    user.ToResponse(),
+	})
+}
+
+// save registra un login pendiente y descarta los expirados
+func (s *oidcStateStore) save(state string, pending oidcLoginState) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	for key, value := range s.pending {
+		if value.expiresAt.Before(now) {
+			delete(s.pending, key)
+		}
+	}
+	s.pending[state] = pending
+}
+
+// consume retorna y elimina un login pendiente si sigue vigente
+func (s *oidcStateStore) consume(state string) (oidcLoginState, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	pending, ok := s.pending[state]
+	delete(s.pending, state)
+	if !ok || pending.expiresAt.Before(time.Now()) {
+		return oidcLoginState{}, false
+	}
+	return pending, true
+}
+
+// randomToken genera un valor aleatorio apto para state y nonce
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionController expone las sesiones activas del usuario autenticado
+type SessionController struct {
+	sessions *services.SessionService
+}
+
+func NewSessionController(sessions *services.SessionService) *SessionController {
+	return &SessionController{sessions: sessions}
+}
+
+// GetSessions devuelve las sesiones activas del usuario autenticado
+func (sc *SessionController) GetSessions(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	sessions, err := sc.sessions.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.session.list_failed")
+		return
+	}
+
+	currentID := getSessionIDFromContext(c)
+	responses := make([]models.SessionResponse, len(sessions))
+	for i := range sessions {
+		responses[i] = sessions[i].ToResponse(currentID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": responses,
+		"count":    len(responses),
+	})
+}
+
+// RevokeSession cierra una sesión concreta del usuario autenticado
+func (sc *SessionController) RevokeSession(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeSessionNotFound, "error.session.not_found")
+		return
+	}
+
+	session, err := sc.sessions.Revoke(c.Request.Context(), userID, id)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeSessionNotFound, "error.session.not_found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.session.revoke_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    localize(c, "message.session.revoked"),
+		"session_id": session.ID,
+	})
+}
+
+// RevokeAllSessions cierra todas las sesiones del usuario ("cerrar sesión en todos lados").
+// Con ?except_current=true se mantiene la sesión desde la que se hace la petición
+func (sc *SessionController) RevokeAllSessions(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	var exceptID uint
+	if c.Query("except_current") == "true" {
+		exceptID = getSessionIDFromContext(c)
+	}
+
+	revoked, err := sc.sessions.RevokeAll(c.Request.Context(), userID, exceptID)
+	if err != nil {
+		respondInternalError(c, err, "error.session.revoke_all_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.session.revoked_all"),
+		"revoked": revoked,
+	})
+}
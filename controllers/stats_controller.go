@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsController expone las estadísticas de productividad del usuario autenticado
+type StatsController struct {
+	stats *services.StatsService
+}
+
+func NewStatsController(stats *services.StatsService) *StatsController {
+	return &StatsController{stats: stats}
+}
+
+// GetStats devuelve el resumen de las tareas del usuario. Acepta from y to (AAAA-MM-DD),
+// interval (day o week) y tz (zona horaria IANA; por defecto la de las preferencias) en la query
+func (sc *StatsController) GetStats(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	request := models.StatsRequest{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Interval: c.Query("interval"),
+		Timezone: c.Query("tz"),
+	}
+	stats, err := sc.stats.Stats(c.Request.Context(), userID, request)
+	if err != nil {
+		respondTaskError(c, err, "error.stats.failed")
+		return
+	}
+
+	locale := requestLocale(c)
+	byStatus := make([]gin.H, len(stats.ByStatus))
+	for i, status := range stats.ByStatus {
+		byStatus[i] = gin.H{
+			"status": status.Status,
+			"label":  stats.Workflow.Label(locale, status.Status),
+			"done":   stats.Workflow.IsDone(status.Status),
+			"count":  status.Count,
+		}
+	}
+
+	series := make([]gin.H, len(stats.CreatedByPeriod))
+	for i := range series {
+		series[i] = gin.H{
+			"period":    stats.Range.PeriodLabel(i),
+			"created":   stats.CreatedByPeriod[i],
+			"completed": stats.CompletedByPeriod[i],
+		}
+	}
+
+	due := gin.H{"no_due_date": stats.NoDueDate}
+	for i, bucket := range models.StatsDueBuckets {
+		due[bucket] = stats.Due[i]
+	}
+
+	from, to := stats.Range.Dates()
+
+	c.JSON(http.StatusOK, gin.H{
+		"timezone":                   stats.Range.Location.String(),
+		"total":                      stats.Total,
+		"completed":                  stats.Completed,
+		"overdue":                    stats.Overdue(),
+		"completion_rate":            math.Round(stats.CompletionRate()*1000) / 1000,
+		"average_completion_seconds": durationSeconds(stats.AverageCompletion),
+		"average_cycle_seconds":      durationSeconds(stats.AverageCycle),
+		"by_status":                  byStatus,
+		"upcoming":                   due,
+		"activity": gin.H{
+			"interval": stats.Range.Interval,
+			"from":     from,
+			"to":       to,
+			"periods":  series,
+		},
+	})
+}
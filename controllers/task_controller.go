@@ -1,55 +1,45 @@
 package controllers
 
 import (
-	"go-task-manager-mvc/config"
+	"errors"
+	"go-task-manager-mvc/i18n"
 	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Helper function para obtener el UserID del contexto
-func getUserIDFromContext(c *gin.Context) (uint, error) {
-	username, exists := c.Get("username")
-	if !exists {
-		return 0, nil
-	}
-
-	var user models.User
-	if err := config.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return 0, err
-	}
+// TaskController expone las tareas del usuario autenticado
+type TaskController struct {
+	tasks *services.TaskService
+}
 
-	return user.ID, nil
+func NewTaskController(tasks *services.TaskService) *TaskController {
+	return &TaskController{tasks: tasks}
 }
 
 // GetTasks devuelve todas las tareas del usuario autenticado
-func GetTasks(c *gin.Context) {
+func (tc *TaskController) GetTasks(c *gin.Context) {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autorizado"})
+		respondUnauthenticated(c)
 		return
 	}
 
-	var tasks []models.Task
-	config.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&tasks)
+	tasks, err := tc.tasks.List(c.Request.Context(), userID, "")
+	if err != nil {
+		respondInternalError(c, err, "error.task.list_failed")
+		return
+	}
 
 	// Agregar información adicional
 	tasksWithInfo := make([]gin.H, len(tasks))
-	for i, task := range tasks {
-		tasksWithInfo[i] = gin.H{
-			"id":           task.ID,
-			"title":        task.Title,
-			"description":  task.Description,
-			"status":       task.Status,
-			"status_color": task.GetStatusColor(),
-			"due_date":     task.DueDate,
-			"is_overdue":   task.IsOverdue(),
-			"is_completed": task.IsCompleted(),
-			"user_id":      task.UserID,
-			"created_at":   task.CreatedAt,
-			"updated_at":   task.UpdatedAt,
-		}
+	for i := range tasks {
+		tasksWithInfo[i] = taskResponse(c, &tasks[i])
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -59,154 +49,344 @@ func GetTasks(c *gin.Context) {
 }
 
 // GetTasksByStatus filtra las tareas por estado
-func GetTasksByStatus(c *gin.Context) {
+func (tc *TaskController) GetTasksByStatus(c *gin.Context) {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autorizado"})
+		respondUnauthenticated(c)
 		return
 	}
 
-	status := c.Query("status")
-
-	// Validar el estado si se proporciona
-	if status != "" && !models.IsValidTaskStatus(status) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":          "Estado inválido",
-			"valid_statuses": models.GetValidTasksStatuesList(),
-		})
+	workflow, err := tc.tasks.Workflow(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.task.list_failed")
 		return
 	}
 
-	var tasks []models.Task
-	query := config.DB.Where("user_id = ?", userID)
+	status := models.NormalizeTaskStatus(c.Query("status"))
+	tasks, err := tc.tasks.List(c.Request.Context(), userID, status)
+	if errors.Is(err, services.ErrInvalidStatus) {
+		valid := workflow.Keys()
+		problem.Respond(c, problem.Validation(
+			problem.Field("status", "oneof", i18n.Msg("validation.oneof", strings.Join(valid, ", "))),
+		).With("valid_statuses", valid))
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.task.list_failed")
+		return
+	}
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	tasksWithInfo := make([]gin.H, len(tasks))
+	for i := range tasks {
+		tasksWithInfo[i] = taskResponse(c, &tasks[i])
 	}
 
-	query.Order("created_at DESC").Find(&tasks)
 	c.JSON(http.StatusOK, gin.H{
-		"tasks":  tasks,
-		"count":  len(tasks),
-		"status": status,
+		"tasks":        tasksWithInfo,
+		"count":        len(tasks),
+		"status":       status,
+		"status_label": workflow.Label(requestLocale(c), status),
 	})
 }
 
+// GetStatuses lista los estados del flujo del usuario con su etiqueta en el idioma de la petición
+func (tc *TaskController) GetStatuses(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	workflow, err := tc.tasks.Workflow(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.task.list_failed")
+		return
+	}
+
+	statuses := make([]gin.H, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		statuses[i] = gin.H{
+			"id":    status.Key,
+			"label": workflow.Label(requestLocale(c), status.Key),
+			"color": workflow.Color(status.Key),
+			"done":  status.Done,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses})
+}
+
 // CreateTask crea una nueva tarea
-func CreateTask(c *gin.Context) {
+func (tc *TaskController) CreateTask(c *gin.Context) {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autorizado"})
+		respondUnauthenticated(c)
 		return
 	}
 
 	var request models.TaskCreateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		problem.Respond(c, problem.Binding(err))
 		return
 	}
 
-	// Convertir request a Task
-	task := request.ToTask(userID)
-
-	// Crear la tarea (las validaciones se ejecutan en BeforeSave)
-	if err := config.DB.Create(&task).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	task, err := tc.tasks.Create(c.Request.Context(), userID, request)
+	if err != nil {
+		respondTaskError(c, err, "error.task.create_failed")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Tarea creada exitosamente",
-		"task": gin.H{
-			"id":           task.ID,
-			"title":        task.Title,
-			"description":  task.Description,
-			"status":       task.Status,
-			"status_color": task.GetStatusColor(),
-			"due_date":     task.DueDate,
-			"is_overdue":   task.IsOverdue(),
-			"user_id":      task.UserID,
-			"created_at":   task.CreatedAt,
-			"updated_at":   task.UpdatedAt,
-		},
+		"message": localize(c, "message.task.created"),
+		"task":    taskResponse(c, task),
 	})
 }
 
 // UpdateTask actualiza una tarea existente
-func UpdateTask(c *gin.Context) {
+func (tc *TaskController) UpdateTask(c *gin.Context) {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autorizado"})
+		respondUnauthenticated(c)
 		return
 	}
 
-	id := c.Param("id")
-	var task models.Task
-
-	// Buscar la tarea y verificar que pertenezca al usuario
-	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&task).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada o no tienes permiso para modificarla"})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_update")
 		return
 	}
 
 	var request models.TaskUpdateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		problem.Respond(c, problem.Binding(err))
+		return
+	}
+
+	task, err := tc.tasks.Update(c.Request.Context(), userID, id, request)
+	if errors.Is(err, services.ErrTaskNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_update")
+		return
+	}
+	if err != nil {
+		respondTaskError(c, err, "error.task.update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.task.updated"),
+		"task":    taskResponse(c, task),
+	})
+}
+
+// GetBoard devuelve las tareas del usuario agrupadas en columnas por estado, en el orden del tablero
+func (tc *TaskController) GetBoard(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	board, err := tc.tasks.Board(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.task.list_failed")
+		return
+	}
+
+	locale := requestLocale(c)
+	columns := make([]gin.H, len(board.Columns))
+	for i, column := range board.Columns {
+		tasks := make([]gin.H, len(column.Tasks))
+		for j := range column.Tasks {
+			tasks[j] = taskResponse(c, &column.Tasks[j])
+		}
+
+		// wip_limit es null en las columnas sin límite
+		var wipLimit any
+		if column.Status.WIPLimit > 0 {
+			wipLimit = column.Status.WIPLimit
+		}
+		columns[i] = gin.H{
+			"status":    column.Status.Key,
+			"label":     board.Workflow.Label(locale, column.Status.Key),
+			"color":     board.Workflow.Color(column.Status.Key),
+			"done":      column.Status.Done,
+			"wip_limit": wipLimit,
+			"count":     len(column.Tasks),
+			"tasks":     tasks,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"columns": columns})
+}
+
+// MoveTask cambia el estado y la posición de una tarea en el tablero
+func (tc *TaskController) MoveTask(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_update")
+		return
+	}
+
+	var request models.TaskMoveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Respond(c, problem.Binding(err))
 		return
 	}
 
-	// Aplicar cambios
-	request.ApplyToTask(&task)
+	task, err := tc.tasks.Move(c.Request.Context(), userID, id, request)
+	if errors.Is(err, services.ErrTaskNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_update")
+		return
+	}
+	if err != nil {
+		respondTaskError(c, err, "error.task.move_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.task.moved"),
+		"task":    taskResponse(c, task),
+	})
+}
+
+// GetTransitions devuelve los cambios de estado de una tarea y cuánto tiempo estuvo en cada estado
+func (tc *TaskController) GetTransitions(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
 
-	// Guardar cambios (las validaciones se ejecutan en BeforeUpdate)
-	if err := config.DB.Save(&task).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found")
 		return
 	}
 
+	task, transitions, err := tc.tasks.Transitions(c.Request.Context(), userID, id)
+	if errors.Is(err, services.ErrTaskNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.task.transitions_failed")
+		return
+	}
+
+	locale := requestLocale(c)
+	workflow := task.Workflow()
+	history := make([]gin.H, len(transitions))
+	for i, transition := range transitions {
+		// El tiempo en el estado llega hasta la transición siguiente; null en el estado actual
+		var duration *time.Duration
+		if i+1 < len(transitions) {
+			d := transitions[i+1].CreatedAt.Sub(transition.CreatedAt)
+			duration = &d
+		}
+
+		// from es null en la transición con la que se creó la tarea
+		var from any
+		if transition.From != "" {
+			from = transition.From
+		}
+		history[i] = gin.H{
+			"from":             from,
+			"to":               transition.To,
+			"to_label":         workflow.Label(locale, transition.To),
+			"at":               transition.CreatedAt,
+			"duration_seconds": durationSeconds(duration),
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Tarea actualizada exitosamente",
-		"task": gin.H{
-			"id":           task.ID,
-			"title":        task.Title,
-			"description":  task.Description,
-			"status":       task.Status,
-			"status_color": task.GetStatusColor(),
-			"due_date":     task.DueDate,
-			"is_overdue":   task.IsOverdue(),
-			"is_completed": task.IsCompleted(),
-			"user_id":      task.UserID,
-			"updated_at":   task.UpdatedAt,
-		},
+		"task":        taskResponse(c, task),
+		"transitions": history,
 	})
 }
 
 // DeleteTask elimina una tarea
-func DeleteTask(c *gin.Context) {
+func (tc *TaskController) DeleteTask(c *gin.Context) {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autorizado"})
+		respondUnauthenticated(c)
 		return
 	}
 
-	id := c.Param("id")
-	var task models.Task
-
-	// Buscar la tarea y verificar que pertenezca al usuario
-	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&task).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada o no tienes permiso para eliminarla"})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_delete")
 		return
 	}
 
-	// Eliminar la tarea (soft delete)
-	if err := config.DB.Delete(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la tarea"})
+	task, err := tc.tasks.Delete(c.Request.Context(), userID, id)
+	if errors.Is(err, services.ErrTaskNotFound) {
+		respondError(c, http.StatusNotFound, problem.CodeTaskNotFound, "error.task.not_found_delete")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err, "error.task.delete_failed")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Tarea eliminada exitosamente",
-		"task_id": id,
+		"message": localize(c, "message.task.deleted"),
+		"task_id": c.Param("id"),
 		"title":   task.Title,
 	})
 }
+
+// taskResponse es la representación de una tarea en las respuestas, con la etiqueta del
+// estado en el idioma de la petición y los datos calculados
+func taskResponse(c *gin.Context, task *models.Task) gin.H {
+	return gin.H{
+		"id":                 task.ID,
+		"title":              task.Title,
+		"description":        task.Description,
+		"status":             task.Status,
+		"status_label":       task.StatusLabel(requestLocale(c)),
+		"status_color":       task.GetStatusColor(),
+		"position":           task.Position,
+		"due_date":           task.DueDate,
+		"is_overdue":         task.IsOverdue(),
+		"is_completed":       task.IsCompleted(),
+		"started_at":         task.StartedAt,
+		"completed_at":       task.CompletedAt,
+		"lead_time_seconds":  durationSeconds(task.LeadTime()),
+		"cycle_time_seconds": durationSeconds(task.CycleTime()),
+		"user_id":            task.UserID,
+		"created_at":         task.CreatedAt,
+		"updated_at":         task.UpdatedAt,
+	}
+}
+
+// durationSeconds retorna la duración en segundos, o nil para que se serialice como null
+func durationSeconds(d *time.Duration) any {
+	if d == nil {
+		return nil
+	}
+	return int64(d.Seconds())
+}
+
+// respondTaskError responde 400 con los campos inválidos, 409 si la columna de destino está
+// llena y delega el resto en respondInternalError
+func respondTaskError(c *gin.Context, err error, key string) {
+	var fieldErrs models.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		respondValidationErrors(c, fieldErrs)
+		return
+	}
+	var wipErr *services.WIPLimitError
+	if errors.As(err, &wipErr) {
+		problem.Respond(c, problem.New(http.StatusConflict, problem.CodeWIPLimitExceeded,
+			"error.task.wip_limit", wipErr.Status, wipErr.Limit).
+			With("task_status", wipErr.Status).With("wip_limit", wipErr.Limit))
+		return
+	}
+	respondInternalError(c, err, key)
+}
@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/i18n"
 	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // Struct separado para login
@@ -20,90 +23,157 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"` // la longitud la valida la política de contraseñas
+}
+
+// UserController expone el registro, el login con contraseña y las preferencias del usuario
+type UserController struct {
+	auth  *services.AuthService
+	users *services.UserService
+}
+
+func NewUserController(auth *services.AuthService, users *services.UserService) *UserController {
+	return &UserController{auth: auth, users: users}
 }
 
 // Registro de usuario
-func RegisterUser(c *gin.Context) {
+func (uc *UserController) RegisterUser(c *gin.Context) {
 	var request RegisterRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
-		return
-	}
-
-	// Verificar que la base de datos esté conectada
-	if config.DB == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Base de datos no conectada"})
-		return
-	}
-
-	// Encriptar contraseña
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al encriptar la contraseña"})
+		problem.Respond(c, problem.Binding(err))
 		return
 	}
 
-	// Crear el usuario
-	user := models.User{
+	user, err := uc.auth.Register(c.Request.Context(), services.RegisterInput{
 		Username: request.Username,
 		Email:    request.Email,
-		Password: string(hashedPassword),
-	}
+		Password: request.Password,
+	})
 
-	// Intentar crear el usuario
-	if err := config.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo registrar el usuario: " + err.Error()})
+	var policyErr *services.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		fields := make([]problem.FieldError, len(policyErr.Violations))
+		for i, violation := range policyErr.Violations {
+			fields[i] = problem.Field("password", "policy", violation)
+		}
+		problem.Respond(c, problem.New(http.StatusBadRequest, problem.CodeWeakPassword,
+			"error.user.weak_password").WithErrors(fields...).With("policy", config.PasswordRules))
+		return
+	case errors.Is(err, services.ErrPasswordHash):
+		respondError(c, http.StatusInternalServerError, problem.CodeInternal, "error.auth.hash_failed")
+		return
+	case errors.Is(err, services.ErrUsernameTaken):
+		respondConflict(c, problem.CodeUsernameTaken, "username", "error.user.username_taken")
+		return
+	case errors.Is(err, services.ErrEmailTaken):
+		respondConflict(c, problem.CodeEmailTaken, "email", "error.user.email_taken")
+		return
+	case err != nil:
+		respondInternalError(c, err, "error.user.register_failed")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Usuario registrado correctamente",
-		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
-		},
+		"message": localize(c, "message.user.registered"),
+		"user":    user.ToResponse(),
 	})
 }
 
 // Login de usuario
-func LoginUser(c *gin.Context) {
+func (uc *UserController) LoginUser(c *gin.Context) {
 	var request LoginRequest
-	var user models.User
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		problem.Respond(c, problem.Binding(err))
+		return
+	}
+
+	token, user, err := uc.auth.Login(c.Request.Context(), request.Email, request.Password, clientInfo(c))
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		respondError(c, http.StatusUnauthorized, problem.CodeInvalidCredentials, "error.auth.user_not_found")
+		return
+	case errors.Is(err, services.ErrWrongPassword):
+		respondError(c, http.StatusUnauthorized, problem.CodeInvalidCredentials, "error.auth.wrong_password")
+		return
+	case err != nil:
+		respondInternalError(c, err, "error.auth.token_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": localize(c, "message.auth.login"),
+		"token":   token,
+		"user":    user.ToResponse(),
+	})
+}
+
+// GetPreferences devuelve las preferencias del usuario autenticado
+func (uc *UserController) GetPreferences(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	user, err := uc.users.Get(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.user.preferences_failed")
 		return
 	}
 
-	// Buscar usuario por email
-	if err := config.DB.Where("email = ?", request.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no encontrado"})
+	c.JSON(http.StatusOK, gin.H{"preferences": preferencesResponse(user)})
+}
+
+// UpdatePreferences cambia el idioma preferido y la zona horaria del usuario autenticado.
+// La respuesta ya usa el idioma nuevo
+func (uc *UserController) UpdatePreferences(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
 		return
 	}
 
-	// Comparar contraseñas
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Contraseña incorrecta"})
+	var request models.PreferencesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Respond(c, problem.Binding(err))
 		return
 	}
 
-	// Generar token JWT
-	token, err := config.GenerateToken(user.Username)
+	user, err := uc.users.SetPreferences(c.Request.Context(), userID, request)
+	var fieldErrs models.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		respondValidationErrors(c, fieldErrs)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al generar token: " + err.Error()})
+		respondInternalError(c, err, "error.user.preferences_failed")
 		return
 	}
 
+	// Sin preferencia se vuelve a negociar con Accept-Language, como en el resto de peticiones
+	locale, ok := i18n.Parse(user.Language)
+	if !ok {
+		locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+	}
+	c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+	c.Header("Content-Language", string(locale))
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Inicio de sesión exitoso",
-		"token":   token,
-		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
-		},
+		"message":     localize(c, "message.user.preferences_updated"),
+		"preferences": preferencesResponse(user),
 	})
 }
+
+func preferencesResponse(user *models.User) models.PreferencesResponse {
+	return models.PreferencesResponse{Language: user.Language, Timezone: user.Timezone, SupportedLanguages: models.SupportedLanguages()}
+}
+
+// respondConflict responde 409 cuando un dato único del registro ya está en uso
+func respondConflict(c *gin.Context, code problem.Code, field, key string) {
+	problem.Respond(c, problem.New(http.StatusConflict, code, key).WithErrors(
+		problem.Field(field, "taken", i18n.Msg(key)),
+	))
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkflowController expone el flujo de trabajo de las tareas del usuario autenticado
+type WorkflowController struct {
+	workflows *services.WorkflowService
+}
+
+func NewWorkflowController(workflows *services.WorkflowService) *WorkflowController {
+	return &WorkflowController{workflows: workflows}
+}
+
+// GetWorkflow devuelve los estados y transiciones del usuario, o el flujo por defecto
+func (wc *WorkflowController) GetWorkflow(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	workflow, err := wc.workflows.Get(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, err, "error.workflow.get_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workflow": workflow.ToResponse(requestLocale(c))})
+}
+
+// UpdateWorkflow sustituye el flujo de trabajo del usuario
+func (wc *WorkflowController) UpdateWorkflow(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	var request models.WorkflowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.Respond(c, problem.Binding(err))
+		return
+	}
+
+	workflow, err := wc.workflows.Replace(c.Request.Context(), userID, request)
+	if err != nil {
+		respondWorkflowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  localize(c, "message.workflow.updated"),
+		"workflow": workflow.ToResponse(requestLocale(c)),
+	})
+}
+
+// ResetWorkflow borra el flujo propio del usuario y vuelve al flujo por defecto
+func (wc *WorkflowController) ResetWorkflow(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondUnauthenticated(c)
+		return
+	}
+
+	workflow, err := wc.workflows.Reset(c.Request.Context(), userID)
+	if err != nil {
+		respondWorkflowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  localize(c, "message.workflow.reset"),
+		"workflow": workflow.ToResponse(requestLocale(c)),
+	})
+}
+
+// respondWorkflowError responde 400 con los campos inválidos, 409 si hay tareas en
+// estados que el flujo nuevo no incluye y delega el resto en respondInternalError
+func respondWorkflowError(c *gin.Context, err error) {
+	var inUse *services.StatusInUseError
+	if errors.As(err, &inUse) {
+		problem.Respond(c, problem.New(http.StatusConflict, problem.CodeStatusInUse,
+			"error.workflow.status_in_use", strings.Join(inUse.Statuses, ", ")).With("statuses", inUse.Statuses))
+		return
+	}
+	respondTaskError(c, err, "error.workflow.update_failed")
+}
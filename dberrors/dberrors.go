@@ -0,0 +1,12 @@
+package dberrors
+
+import "errors"
+
+// Errores de disponibilidad de la base de datos. Están en un paquete sin dependencias para que
+// repositories los retorne y problem los traduzca sin que uno importe al otro
+var (
+	// ErrQueryTimeout indica que la consulta no terminó antes del plazo de la petición
+	ErrQueryTimeout = errors.New("la base de datos no respondió a tiempo")
+	// ErrUnavailable indica que no se pudo conectar con la base de datos
+	ErrUnavailable = errors.New("base de datos no disponible")
+)
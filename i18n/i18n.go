@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Locale es un idioma soportado por la API
+type Locale string
+
+const (
+	Spanish Locale = "es"
+	English Locale = "en"
+	// Default es el idioma de las respuestas cuando el cliente no pide otro
+	Default = Spanish
+)
+
+// Idiomas soportados; el primero es el que se usa si ninguno coincide
+var supported = []Locale{Spanish, English}
+
+var matcher = language.NewMatcher([]language.Tag{language.Spanish, language.English})
+
+//go:embed locales/*.json
+var files embed.FS
+
+// catalogs contiene los mensajes de cada idioma, indexados por clave
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[Locale]map[string]string {
+	result := make(map[Locale]map[string]string, len(supported))
+	for _, locale := range supported {
+		data, err := files.ReadFile(path.Join("locales", string(locale)+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: falta el catálogo %s: %v", locale, err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: catálogo %s inválido: %v", locale, err))
+		}
+		result[locale] = messages
+	}
+	return result
+}
+
+// Supported retorna los idiomas soportados
+func Supported() []Locale {
+	return append([]Locale(nil), supported...)
+}
+
+// Keys retorna las claves del catálogo de un idioma
+func Keys(locale Locale) []string {
+	keys := make([]string, 0, len(catalogs[locale]))
+	for key := range catalogs[locale] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Parse reconoce un idioma soportado ("en", "en-US", "ES"...)
+func Parse(value string) (Locale, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), "-")
+	for _, locale := range supported {
+		if base == string(locale) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate elige el idioma a partir de la cabecera Accept-Language
+func Negotiate(acceptLanguage string) Locale {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// T traduce el mensaje key al idioma indicado. Si falta la traducción se usa el idioma por defecto
+func T(locale Locale, key string, args ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Message es un mensaje pendiente de traducir: se guarda la clave y se traduce al responder
+type Message struct {
+	Key  string
+	Args []any
+}
+
+// Msg crea un mensaje con sus argumentos
+func Msg(key string, args ...any) Message {
+	return Message{Key: key, Args: args}
+}
+
+// In traduce el mensaje al idioma indicado
+func (m Message) In(locale Locale) string {
+	return T(locale, m.Key, m.Args...)
+}
+
+func (m Message) String() string {
+	return m.In(Default)
+}
+
+type contextKey struct{}
+
+// WithLocale guarda el idioma de la petición en el contexto
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext retorna el idioma de la petición, o el idioma por defecto
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return Default
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"go-task-manager-mvc/config"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New crea un logger que escribe en w con el nivel y el formato configurados
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// Setup instala el logger como logger por defecto de slog.
+// Lo que todavía se escriba con el paquete log también sale por él
+func Setup(cfg config.LogConfig) {
+	slog.SetDefault(New(cfg, os.Stderr))
+}
+
+type requestIDKey struct{}
+
+// WithRequestID guarda el ID de la petición en ctx
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID retorna el ID de la petición guardado en ctx, o "" si no hay
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext retorna el logger por defecto con el ID de la petición y la traza de ctx
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if requestID := RequestID(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logger = logger.With("trace_id", span.TraceID().String(), "span_id", span.SpanID().String())
+	}
+	return logger
+}
@@ -1,22 +1,159 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"go-task-manager-mvc/config"
-	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/metrics"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/ratelimit"
+	"go-task-manager-mvc/repositories"
 	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/server"
+	"go-task-manager-mvc/tracing"
+	"go-task-manager-mvc/workers"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // zonas horarias de /api/stats aunque la imagen no incluya tzdata
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	config.ConnectDB()
-	log.Println("Conectado a la base de datos")
-	models.MigrateModels()
-	log.Println("Migraciones completadas")
-
-	r := gin.Default()
-	routes.SetupRoutes(r)
-	log.Println("Servidor corriendo en http://localhost:8080")
-	r.Run(":8080")
+	configFile := flag.String("config", "", "archivo de configuración YAML o TOML (por defecto CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "mostrar la configuración efectiva (sin secretos) y salir")
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(*configFile, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Configuración inválida:\n", err)
+	}
+	if *printConfig {
+		fmt.Print(cfg)
+		return
+	}
+	logging.Setup(cfg.Log)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("Error al configurar las trazas", err)
+	}
+
+	if err := config.LoadJWTKeys(cfg.JWT); err != nil {
+		fatal("Error al cargar las claves JWT", err)
+	}
+	if err := config.LoadPasswordSettings(cfg.Password); err != nil {
+		fatal("Error en la política de contraseñas", err)
+	}
+
+	if err := config.ConnectDB(cfg.Database, tracing.NewGormPlugin()); err != nil {
+		fatal("Error al conectar con la base de datos", err)
+	}
+	slog.Info("Conectado a la base de datos", "host", cfg.Database.Host, "database", cfg.Database.Name)
+	if err := registerDBMetrics(); err != nil {
+		fatal("Error al registrar las métricas de la base de datos", err)
+	}
+
+	// Las migraciones se aplican aparte (task-api migrate up): no servir con un esquema desactualizado
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		fatal("Migraciones inválidas", err)
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		fatal("Esquema desactualizado. Ejecuta: task-api migrate up", err)
+	}
+
+	if cfg.OIDC.Enabled() {
+		if err := config.InitOIDC(context.Background(), cfg.OIDC); err != nil {
+			fatal("Error al configurar el proveedor OIDC", err)
+		}
+		slog.Info("Login con proveedor de identidad habilitado", "issuer", cfg.OIDC.IssuerURL)
+	}
+
+	repos := repositories.NewGormRepositoriesWithReplicas(config.DB, config.Replicas)
+
+	// Tareas de fondo: se detienen después de drenar las peticiones en curso
+	jobs := []workers.Worker{
+		workers.NewSessionCleanup(repos.Sessions, cfg.Workers.SessionCleanupInterval.Std(), cfg.Workers.SessionRetention.Std()),
+		workers.NewTaskMetrics(repos.Tasks, cfg.Workers.TaskMetricsInterval.Std()),
+		workers.NewIdempotencyCleanup(repos.Idempotency, cfg.Workers.IdempotencyCleanupInterval.Std()),
+	}
+	if config.Replicas != nil {
+		jobs = append(jobs, workers.NewReplicaHealthCheck(config.Replicas, cfg.Database.ReplicaHealthInterval.Std()))
+	}
+	backgroundWorkers := workers.NewManager(jobs...)
+	backgroundWorkers.Start()
+
+	gin.SetMode(cfg.Server.GinMode)
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("TRUSTED_PROXIES inválido", err)
+	}
+	r.Use(
+		middleware.Tracing(cfg.Tracing.ServiceName),
+		middleware.RequestID(),
+		middleware.Locale(),
+		middleware.AccessLog(),
+		middleware.Metrics(),
+		middleware.Recovery(),
+		middleware.QueryDeadline(cfg.Database.QueryTimeout.Std()),
+	)
+	routes.SetupRoutes(r, repos,
+		routes.WithRateLimits(ratelimit.NewMemoryStore(), cfg.RateLimit),
+		routes.WithIdempotencyTTL(cfg.Idempotency.TTL.Std()),
+		routes.WithIdempotencyLease(cfg.Idempotency.Lease.Std()),
+	)
+	routes.SetupHealthRoutes(r, backgroundWorkers)
+	routes.SetupMetricsRoutes(r)
+
+	srv := server.New(cfg.Server, r)
+	srv.OnShutdown(backgroundWorkers.Stop)
+	srv.OnShutdown(func(ctx context.Context) error { return config.CloseDB() })
+	srv.OnShutdown(shutdownTracing)
+
+	// SIGINT (Ctrl+C) y SIGTERM (orquestador) inician el apagado ordenado
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Servidor escuchando", "addr", "http://localhost:"+cfg.Server.Port)
+	if err := srv.ListenAndServe(ctx); err != nil {
+		fatal("Error al apagar el servidor", err)
+	}
+	slog.Info("Servidor detenido")
+}
+
+// registerDBMetrics publica las estadísticas de los pools del primario y de las réplicas
+func registerDBMetrics() error {
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := metrics.RegisterDB("primary", sqlDB); err != nil {
+		return err
+	}
+	for addr, replica := range config.Replicas.SQLDBs() {
+		if err := metrics.RegisterDB("replica:"+addr, replica); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fatal registra un error que impide seguir y termina el proceso
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prefijo de las métricas propias de la aplicación
+const namespace = "taskapi"
+
+// Etiqueta de ruta para las peticiones que no coinciden con ninguna ruta registrada
+const UnmatchedRoute = "unmatched"
+
+// Registry contiene las métricas que expone /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests cuenta las peticiones por método, plantilla de ruta y estado
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Peticiones HTTP atendidas.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration mide la latencia de las peticiones por método, plantilla de ruta y estado
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latencia de las peticiones HTTP.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route", "status"})
+
+	// Logins cuenta los inicios de sesión por método (password, oidc) y resultado (success, failure)
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Inicios de sesión por método y resultado.",
+	}, []string{"method", "result"})
+
+	// Tasks es el número de tareas por estado, de todos los usuarios
+	Tasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tasks",
+		Help:      "Tareas por estado.",
+	}, []string{"status"})
+
+	// RateLimited cuenta las peticiones rechazadas por superar la cuota, por grupo de rutas
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Peticiones rechazadas por límite de peticiones.",
+	}, []string{"group"})
+
+	// TasksOverdue es el número de tareas vencidas sin completar
+	TasksOverdue = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tasks_overdue",
+		Help:      "Tareas vencidas sin completar.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		Logins,
+		RateLimited,
+		Tasks,
+		TasksOverdue,
+	)
+}
+
+// Handler sirve las métricas en el formato de Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RecordLogin cuenta un inicio de sesión correcto o rechazado
+func RecordLogin(method string, success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	Logins.WithLabelValues(method, result).Inc()
+}
+
+// SetTaskCounts actualiza las métricas de tareas. Los estados sin tareas se publican a 0
+func SetTaskCounts(statuses []string, byStatus map[string]int64, overdue int64) {
+	Tasks.Reset()
+	for _, status := range statuses {
+		Tasks.WithLabelValues(status).Set(0)
+	}
+	for status, count := range byStatus {
+		Tasks.WithLabelValues(status).Set(float64(count))
+	}
+	TasksOverdue.Set(float64(overdue))
+}
+
+var (
+	dbCollectorsMu sync.Mutex
+	dbCollectors   = make(map[string]prometheus.Collector)
+)
+
+// RegisterDB publica las estadísticas del pool de conexiones con la etiqueta db_name=name.
+// Si ya había un pool con ese nombre lo reemplaza
+func RegisterDB(name string, db *sql.DB) error {
+	dbCollectorsMu.Lock()
+	defer dbCollectorsMu.Unlock()
+
+	if previous, ok := dbCollectors[name]; ok {
+		Registry.Unregister(previous)
+	}
+	collector := collectors.NewDBStatsCollector(db, name)
+	if err := Registry.Register(collector); err != nil {
+		return err
+	}
+	dbCollectors[name] = collector
+	return nil
+}
@@ -1,34 +1,138 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+	"go-task-manager-mvc/tracing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// AuthMiddleware protege rutas que requieren autenticación JWT
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware protege rutas que requieren autenticación.
+// Acepta un token JWT (Authorization: Bearer ...) o una API key
+// (X-API-Key: ... o Authorization: ApiKey ...)
+func AuthMiddleware(sessions *services.SessionService, apiKeys *services.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Falta el token de autorización"})
-			c.Abort()
-			return
+		// El span cubre solo la autenticación, no el resto de la petición
+		ctx, span := tracing.Start(c.Request.Context(), "AuthMiddleware")
+		authenticated := authenticate(ctx, c, sessions, apiKeys)
+		span.SetAttributes(attribute.Bool("auth.authenticated", authenticated))
+		if method, ok := c.Get("auth_method"); ok {
+			span.SetAttributes(attribute.String("auth.method", method.(string)))
+		}
+		span.End()
+
+		if authenticated {
+			c.Next()
 		}
+	}
+}
+
+// authenticate guarda el usuario de la petición en el contexto o la corta con el error correspondiente
+func authenticate(ctx context.Context, c *gin.Context, sessions *services.SessionService, apiKeys *services.APIKeyService) bool {
+	authHeader := c.GetHeader("Authorization")
+	apiKey := c.GetHeader("X-API-Key")
+	if apiKey == "" && strings.HasPrefix(authHeader, "ApiKey ") {
+		apiKey = strings.TrimPrefix(authHeader, "ApiKey ")
+	}
+
+	if apiKey != "" {
+		return authenticateAPIKey(ctx, c, apiKeys, strings.TrimSpace(apiKey))
+	}
+
+	if authHeader == "" {
+		abortWithError(c, http.StatusUnauthorized, problem.CodeUnauthenticated, "error.auth.missing_token")
+		return false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	_, span := tracing.Start(ctx, "ValidateToken")
+	claims, err := config.ValidateToken(tokenString)
+	span.End()
+	if err != nil {
+		abortWithError(c, http.StatusUnauthorized, problem.CodeInvalidToken, "error.auth.invalid_token")
+		return false
+	}
+
+	// El token solo es válido mientras su sesión no haya sido revocada
+	session, err := sessions.Authenticate(ctx, claims)
+	if abortIfUnavailable(c, err) {
+		return false
+	}
+	if err != nil {
+		abortWithError(c, http.StatusUnauthorized, problem.CodeSessionRevoked, "error.auth.session_revoked")
+		return false
+	}
+
+	// Guardar usuario en el contexto para usarlo en controladores
+	c.Set("user_id", session.UserID)
+	c.Set("username", claims.Username)
+	c.Set("session_id", session.ID)
+	c.Set("auth_method", "jwt")
+	applyUserLanguage(c, session.User.Language)
+	return true
+}
+
+// authenticateAPIKey valida una API key y guarda el usuario y sus scopes en el contexto
+func authenticateAPIKey(ctx context.Context, c *gin.Context, apiKeys *services.APIKeyService, key string) bool {
+	apiKey, err := apiKeys.Authenticate(ctx, key)
+	if abortIfUnavailable(c, err) {
+		return false
+	}
+	if errors.Is(err, services.ErrAPIKeyInactive) {
+		abortWithError(c, http.StatusUnauthorized, problem.CodeInvalidAPIKey, "error.auth.api_key_inactive")
+		return false
+	}
+	if err != nil {
+		abortWithError(c, http.StatusUnauthorized, problem.CodeInvalidAPIKey, "error.auth.api_key_invalid")
+		return false
+	}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := config.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
-			c.Abort()
+	c.Set("user_id", apiKey.UserID)
+	c.Set("username", apiKey.User.Username)
+	c.Set("scopes", apiKey.ScopeList())
+	c.Set("auth_method", "api_key")
+	applyUserLanguage(c, apiKey.User.Language)
+	return true
+}
+
+// abortIfUnavailable corta la petición con 503/504 si no se pudo consultar la base de datos,
+// para no confundir una caída con credenciales inválidas
+func abortIfUnavailable(c *gin.Context, err error) bool {
+	p := problem.Unavailable(err)
+	if p == nil {
+		return false
+	}
+	problem.Abort(c, p)
+	return true
+}
+
+// RequireScope exige un scope concreto a las peticiones autenticadas con API key.
+// Las sesiones JWT no tienen scopes en el contexto y tienen acceso completo
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("scopes")
+		if !exists {
+			c.Next()
 			return
 		}
 
-		// Guardar usuario en el contexto para usarlo en controladores
-		c.Set("username", claims.Username)
-		c.Next()
+		scopes, _ := value.([]string)
+		for _, s := range scopes {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		problem.Abort(c, problem.New(http.StatusForbidden, problem.CodeInsufficientScope,
+			"error.auth.insufficient_scope").With("required_scope", scope))
 	}
 }
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryDeadline limita el tiempo que una petición puede esperar a la base de datos.
+// Las consultas usan el contexto de la petición, así que al vencer el plazo fallan
+// y el controlador responde 504 en lugar de dejar al cliente esperando
+func QueryDeadline(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
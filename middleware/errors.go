@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"go-task-manager-mvc/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError corta la petición con un error problem+json con el código indicado.
+// key es la clave del detalle en el catálogo de mensajes
+func abortWithError(c *gin.Context, status int, code problem.Code, key string) {
+	problem.Abort(c, problem.New(status, code, key))
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cabecera con la que el cliente identifica una operación que puede reintentar
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Cabecera que marca una respuesta repetida de una petición anterior
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// Claves aceptadas: ASCII visible, como los UUID que generan los clientes
+var validIdempotencyKey = regexp.MustCompile(`^[\x21-\x7E]{1,255}$`)
+
+// Plazo para guardar la respuesta, independiente del contexto de la petición
+const idempotencySaveTimeout = 5 * time.Second
+
+// Idempotency repite la respuesta guardada si el usuario reenvía una petición con la misma
+// Idempotency-Key, sin volver a ejecutarla. Mientras la original está en curso responde 409.
+// Las respuestas 5xx no se guardan para que el cliente pueda reintentar. Debe ir después de AuthMiddleware
+func Idempotency(keys *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey.MatchString(key) {
+			abortWithError(c, http.StatusBadRequest, problem.CodeInvalidIdempotencyKey, "error.idempotency.invalid_key")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, problem.CodeInvalidBody, "error.body_unreadable")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID, _ := c.Get("user_id")
+		id, _ := userID.(uint)
+		ctx := c.Request.Context()
+		record, started, err := keys.Begin(ctx, id, key, requestHash(c.Request, body))
+		switch {
+		case errors.Is(err, services.ErrIdempotencyInProgress):
+			c.Header("Retry-After", "1")
+			abortWithError(c, http.StatusConflict, problem.CodeIdempotencyInProgress, "error.idempotency.in_progress")
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			abortWithError(c, http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, "error.idempotency.reused")
+			return
+		case abortIfUnavailable(c, err):
+			return
+		case err != nil:
+			c.Error(err)
+			abortWithError(c, http.StatusInternalServerError, problem.CodeInternal, "error.idempotency.failed")
+			return
+		case !started:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// La respuesta se guarda aunque el cliente se haya desconectado: es el caso que provoca los reintentos
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencySaveTimeout)
+		defer cancel()
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				keys.Release(saveCtx, record)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			err = keys.Release(saveCtx, record)
+		} else {
+			err = keys.Complete(saveCtx, record, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			logging.FromContext(ctx).Warn("No se pudo guardar la respuesta idempotente", "error", err)
+		}
+	}
+}
+
+// requestHash identifica la petición por método, ruta y cuerpo
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// bodyRecorder copia el cuerpo de la respuesta mientras se envía al cliente
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
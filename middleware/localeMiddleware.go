@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"go-task-manager-mvc/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale elige el idioma de la respuesta a partir de Accept-Language y lo guarda en el contexto
+// de la petición (ver i18n.FromContext). AuthMiddleware lo cambia por el idioma preferido del usuario
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Language")
+		setLocale(c, i18n.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// setLocale cambia el idioma de la petición y lo indica en Content-Language
+func setLocale(c *gin.Context, locale i18n.Locale) {
+	c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+	c.Header("Content-Language", string(locale))
+}
+
+// applyUserLanguage usa el idioma preferido del usuario autenticado, si lo tiene
+func applyUserLanguage(c *gin.Context, language string) {
+	if locale, ok := i18n.Parse(language); ok {
+		setLocale(c, locale)
+	}
+}
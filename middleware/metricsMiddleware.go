@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-task-manager-mvc/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics cuenta las peticiones y mide su latencia. La ruta es la plantilla de gin
+// (/api/tasks/:id), no la URL, para que cada ID no genere una serie nueva
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		method, route := c.Request.Method, c.FullPath()
+		if route == "" {
+			// Sin ruta el método tampoco está acotado: cualquier cliente puede inventarlo
+			route = metrics.UnmatchedRoute
+			if !standardMethods[method] {
+				method = "OTHER"
+			}
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/metrics"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey identifica al cliente cuya cuota consume la petición
+type RateLimitKey func(c *gin.Context) string
+
+// ByClientIP agrupa las peticiones por IP. Solo se usa X-Forwarded-For si viene de un proxy de confianza
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser agrupa las peticiones por usuario autenticado (o por IP si no hay usuario).
+// Debe ir después de AuthMiddleware
+func ByUser(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return ByClientIP(c)
+}
+
+// RateLimit limita las peticiones del grupo con un token bucket por cliente. Informa de la cuota
+// con las cabeceras RateLimit-* y responde 429 con Retry-After cuando se agota.
+// Si el store falla se deja pasar la petición: es preferible a rechazar todo el tráfico
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit, key RateLimitKey) gin.HandlerFunc {
+	policy := fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Period))
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result, err := store.Take(ctx, group+":"+key(c), limit)
+		if err != nil {
+			logging.FromContext(ctx).Warn("No se pudo comprobar el límite de peticiones", "group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(group).Inc()
+			retryAfter := seconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			problem.Abort(c, problem.New(http.StatusTooManyRequests, problem.CodeRateLimited,
+				"error.rate_limited").With("retry_after", retryAfter))
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds redondea hacia arriba: un cliente que espera lo indicado ya tiene cuota
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
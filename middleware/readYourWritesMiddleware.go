@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"go-task-manager-mvc/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadYourWrites hace que, una vez que la petición escribe en la base de datos,
+// sus lecturas posteriores vayan al primario en lugar de a una réplica
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(repositories.TrackWrites(c.Request.Context()))
+		c.Next()
+	}
+}
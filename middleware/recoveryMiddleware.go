@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery convierte un panic en un 500 y lo registra con la traza y el ID de la petición
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// El servidor usa este panic para cortar la respuesta a propósito
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logging.FromContext(c.Request.Context()).Error("Panic al atender la petición",
+				"panic", fmt.Sprint(recovered),
+				"stack", string(debug.Stack()),
+			)
+			abortWithError(c, http.StatusInternalServerError, problem.CodeInternal, "error.internal")
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"go-task-manager-mvc/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cabecera con la que se recibe y se devuelve el ID de la petición
+const RequestIDHeader = "X-Request-ID"
+
+// IDs aceptados de otros servicios: sin espacios ni caracteres de control, para no ensuciar los logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID asigna un ID a cada petición. Respeta el X-Request-ID recibido si es válido,
+// lo guarda en el contexto de la petición (ver logging.RequestID) y lo devuelve en la respuesta
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
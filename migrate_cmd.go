@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/migrations"
+)
+
+const migrateUsage = `Uso: task-api [-config archivo] migrate <comando>
+
+Comandos:
+  up                 aplica todas las migraciones pendientes
+  down [n]           revierte las últimas n migraciones (por defecto 1)
+  status             muestra qué migraciones están aplicadas
+  create <nombre>    genera los archivos de una nueva migración (-dir, por defecto migrations/sql)
+`
+
+// runMigrate ejecuta el subcomando migrate
+func runMigrate(configFile string, args []string) error {
+	if len(args) == 0 {
+		fmt.Print(migrateUsage)
+		return errors.New("falta el comando de migrate")
+	}
+
+	// create solo escribe archivos: no necesita configuración ni base de datos
+	if args[0] == "create" {
+		flags := flag.NewFlagSet("create", flag.ContinueOnError)
+		dir := flags.String("dir", migrations.DefaultDir, "directorio de las migraciones")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New("uso: migrate create <nombre>")
+		}
+		up, down, err := migrations.Create(*dir, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Creada %s\nCreada %s\n", up, down)
+		return nil
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("configuración inválida:\n%w", err)
+	}
+	if err := config.ConnectDB(cfg.Database); err != nil {
+		return err
+	}
+	defer config.CloseDB()
+
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			log.Printf("Aplicada %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("El esquema ya está actualizado")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("número de migraciones inválido: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			log.Printf("Revertida %04d_%s", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pendiente"
+			if status.Applied {
+				state = "aplicada " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+
+	fmt.Print(migrateUsage)
+	return fmt.Errorf("comando de migrate desconocido: %q", args[0])
+}
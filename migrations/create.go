@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Directorio de las migraciones incluidas en el binario, relativo a la raíz del proyecto
+const DefaultDir = "migrations/sql"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create genera los archivos vacíos de una nueva migración en dir con la siguiente versión
+func Create(dir string, name string) (string, string, error) {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("nombre de migración vacío")
+	}
+
+	existing, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version int64 = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- revertir "+name+"\n"), 0644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
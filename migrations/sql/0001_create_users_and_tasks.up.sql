@@ -0,0 +1,30 @@
+-- Esquema inicial. IF NOT EXISTS permite adoptar bases creadas antes con AutoMigrate
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `username` varchar(191) NOT NULL,
+  `email` varchar(191) NOT NULL,
+  `password` longtext NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_users_deleted_at` (`deleted_at`),
+  CONSTRAINT `uni_users_username` UNIQUE (`username`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE IF NOT EXISTS `tasks` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `title` longtext NOT NULL,
+  `description` longtext,
+  `status` varchar(191) DEFAULT 'pendiente',
+  `due_date` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_tasks_user_id` (`user_id`),
+  INDEX `idx_tasks_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_users_tasks` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
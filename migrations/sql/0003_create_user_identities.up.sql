@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `user_identities` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `issuer` varchar(255) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `email` varchar(255),
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_identities_user_id` (`user_id`),
+  UNIQUE INDEX `idx_identity_issuer_subject` (`issuer`, `subject`),
+  CONSTRAINT `fk_user_identities_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `sessions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `user_agent` varchar(512),
+  `ip` varchar(64),
+  `created_at` datetime(3) NULL,
+  `last_seen_at` datetime(3) NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `revoked_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_sessions_user_id` (`user_id`),
+  CONSTRAINT `fk_sessions_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `idempotency_key` varchar(255) NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `status_code` int NOT NULL DEFAULT 0,
+  `content_type` varchar(255),
+  `response_body` mediumblob,
+  `created_at` datetime(3) NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `locked_until` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_idempotency_keys_user_key` (`user_id`, `idempotency_key`),
+  INDEX `idx_idempotency_keys_expires_at` (`expires_at`),
+  CONSTRAINT `fk_idempotency_keys_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
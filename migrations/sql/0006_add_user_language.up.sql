@@ -0,0 +1,2 @@
+-- Idioma preferido del usuario para los mensajes de la API. Vacío: se usa Accept-Language
+ALTER TABLE `users` ADD COLUMN `language` varchar(8) NOT NULL DEFAULT '';
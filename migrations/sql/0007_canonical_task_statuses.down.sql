@@ -0,0 +1,8 @@
+UPDATE `tasks` SET `status` = CASE `status`
+  WHEN 'pending' THEN 'pendiente'
+  WHEN 'in_progress' THEN 'en progreso'
+  WHEN 'completed' THEN 'completada'
+  ELSE `status`
+END;
+
+ALTER TABLE `tasks` ALTER COLUMN `status` SET DEFAULT 'pendiente';
@@ -0,0 +1,9 @@
+-- Los estados se guardan con identificadores neutros; los textos en español pasan a ser etiquetas traducidas
+UPDATE `tasks` SET `status` = CASE `status`
+  WHEN 'pendiente' THEN 'pending'
+  WHEN 'en progreso' THEN 'in_progress'
+  WHEN 'completada' THEN 'completed'
+  ELSE `status`
+END;
+
+ALTER TABLE `tasks` ALTER COLUMN `status` SET DEFAULT 'pending';
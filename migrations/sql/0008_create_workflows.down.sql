@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `workflow_transitions`;
+DROP TABLE IF EXISTS `workflow_statuses`;
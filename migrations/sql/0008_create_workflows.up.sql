@@ -0,0 +1,26 @@
+-- Flujo de trabajo propio de cada usuario: sus estados y las transiciones permitidas.
+-- Sin filas el usuario usa el flujo por defecto (pending, in_progress, completed)
+CREATE TABLE IF NOT EXISTS `workflow_statuses` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `status_key` varchar(64) NOT NULL,
+  `name` varchar(100) NOT NULL DEFAULT '',
+  `color` varchar(32) NOT NULL DEFAULT '',
+  `position` int NOT NULL DEFAULT 0,
+  `is_done` boolean NOT NULL DEFAULT false,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_workflow_statuses_user_key` (`user_id`, `status_key`),
+  CONSTRAINT `fk_workflow_statuses_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `workflow_transitions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `from_status` varchar(64) NOT NULL,
+  `to_status` varchar(64) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_workflow_transitions_user_pair` (`user_id`, `from_status`, `to_status`),
+  CONSTRAINT `fk_workflow_transitions_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
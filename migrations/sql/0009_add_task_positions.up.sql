@@ -0,0 +1,2 @@
+-- Orden de las tareas dentro de cada columna del tablero
+ALTER TABLE `tasks` ADD COLUMN `position` int NOT NULL DEFAULT 0;
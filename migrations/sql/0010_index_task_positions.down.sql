@@ -0,0 +1 @@
+DROP INDEX `idx_tasks_user_status_position` ON `tasks`;
@@ -0,0 +1,2 @@
+-- Las columnas del tablero se leen por usuario y estado, en orden de posición
+CREATE INDEX `idx_tasks_user_status_position` ON `tasks` (`user_id`, `status`, `position`);
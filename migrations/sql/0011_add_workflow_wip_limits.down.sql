@@ -0,0 +1 @@
+ALTER TABLE `workflow_statuses` DROP COLUMN `wip_limit`;
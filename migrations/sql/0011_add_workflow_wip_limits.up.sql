@@ -0,0 +1,2 @@
+-- Límite de tareas por estado (0: sin límite)
+ALTER TABLE `workflow_statuses` ADD COLUMN `wip_limit` int NOT NULL DEFAULT 0;
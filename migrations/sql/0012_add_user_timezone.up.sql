@@ -0,0 +1,2 @@
+-- Zona horaria del usuario (nombre IANA) para las estadísticas. Vacío: UTC
+ALTER TABLE `users` ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Momento en que cada tarea empezó
+ALTER TABLE `tasks` ADD COLUMN `started_at` datetime(3) NULL;
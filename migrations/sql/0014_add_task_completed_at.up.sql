@@ -0,0 +1,2 @@
+-- Momento en que cada tarea se completó
+ALTER TABLE `tasks` ADD COLUMN `completed_at` datetime(3) NULL;
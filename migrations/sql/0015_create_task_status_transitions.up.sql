@@ -0,0 +1,13 @@
+-- Registro de los cambios de estado de cada tarea
+CREATE TABLE IF NOT EXISTS `task_status_transitions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `task_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `from_status` varchar(64) NOT NULL DEFAULT '',
+  `to_status` varchar(64) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_task_status_transitions_task` (`task_id`, `created_at`),
+  CONSTRAINT `fk_task_status_transitions_task` FOREIGN KEY (`task_id`) REFERENCES `tasks` (`id`),
+  CONSTRAINT `fk_task_status_transitions_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
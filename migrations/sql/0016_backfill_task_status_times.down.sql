@@ -0,0 +1,2 @@
+-- El relleno no se deshace: las fechas desaparecen al revertir las columnas
+SELECT 1;
@@ -0,0 +1,22 @@
+-- Solo datos, sin DDL: si falla a la mitad se puede volver a aplicar entera.
+-- Las tareas ya completadas toman su última modificación como fecha de finalización:
+-- las que están en un estado done de su flujo y las completed de los usuarios sin flujo propio
+UPDATE `tasks`, `workflow_statuses` ws
+SET tasks.completed_at = tasks.updated_at
+WHERE ws.user_id = tasks.user_id AND ws.status_key = tasks.status AND ws.is_done AND tasks.completed_at IS NULL;
+UPDATE `tasks` LEFT JOIN `workflow_statuses` ws ON ws.user_id = tasks.user_id
+SET tasks.completed_at = tasks.updated_at
+WHERE tasks.status = 'completed' AND ws.id IS NULL AND tasks.completed_at IS NULL;
+
+-- No se sabe cuándo empezó cada tarea, así que started_at es una aproximación:
+-- las completadas toman su creación (su cycle time queda igual al lead time) y las que
+-- están en curso, fuera del estado inicial y sin completar, su última modificación
+UPDATE `tasks`
+SET started_at = created_at
+WHERE completed_at IS NOT NULL AND started_at IS NULL;
+UPDATE `tasks`, `workflow_statuses` ws
+SET tasks.started_at = tasks.updated_at
+WHERE ws.user_id = tasks.user_id AND ws.status_key = tasks.status AND ws.position > 0 AND NOT ws.is_done AND tasks.started_at IS NULL;
+UPDATE `tasks` LEFT JOIN `workflow_statuses` ws ON ws.user_id = tasks.user_id
+SET tasks.started_at = tasks.updated_at
+WHERE tasks.status = 'in_progress' AND ws.id IS NULL AND tasks.started_at IS NULL;
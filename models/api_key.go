@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes que puede tener una API key
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	// Solo las sesiones JWT los tienen; una API key no puede gestionar API keys, sesiones, el perfil ni el flujo de trabajo
+	ScopeAPIKeysManage  = "api_keys:manage"
+	ScopeSessionsManage = "sessions:manage"
+	ScopeProfileManage  = "profile:manage"
+	ScopeWorkflowManage = "workflow:manage"
+)
+
+// Retorna los scopes que se pueden asignar a una API key
+func GrantableAPIKeyScopes() []string {
+	return []string{
+		ScopeTasksRead,
+		ScopeTasksWrite,
+	}
+}
+
+// APIKey es una clave personal para scripts e integraciones.
+// Solo se guarda el hash; el valor completo se muestra una única vez al crearla
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	User       User       `gorm:"foreignKey:UserID" json:"-"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;uniqueIndex;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null" json:"-"`
+	Scopes     string     `gorm:"size:255;not null" json:"-"` // separados por espacios
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScopeList retorna los scopes de la clave como lista
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// IsExpired verifica si la clave ya expiró
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now())
+}
+
+// IsRevoked verifica si la clave fue revocada
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsActive verifica si la clave puede usarse para autenticar
+func (k *APIKey) IsActive() bool {
+	return !k.IsRevoked() && !k.IsExpired()
+}
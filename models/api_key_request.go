@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// representa los datos para crear una API key
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required,min=3,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// representa una API key en las respuestas (nunca incluye el hash)
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// convierte APIKeyCreateRequest a APIKey (sin prefijo ni hash, que se generan aparte)
+func (r *APIKeyCreateRequest) ToAPIKey(userID uint) APIKey {
+	return APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(r.Name),
+		Scopes:    strings.Join(uniqueScopes(r.Scopes), " "),
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// convierte APIKey a APIKeyResponse
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		LastUsedAt: k.LastUsedAt,
+		ExpiresAt:  k.ExpiresAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// elimina scopes repetidos manteniendo el orden
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
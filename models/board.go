@@ -0,0 +1,30 @@
+package models
+
+// BoardColumn es una columna del tablero: un estado del flujo con sus tareas en orden
+type BoardColumn struct {
+	Status WorkflowStatus
+	Tasks  []Task
+}
+
+// Board son las tareas de un usuario agrupadas por los estados de su flujo de trabajo
+type Board struct {
+	Workflow *Workflow
+	Columns  []BoardColumn
+}
+
+// NewBoard reparte las tareas en las columnas del flujo manteniendo su orden.
+// Las tareas en estados que el flujo no incluye no aparecen en el tablero
+func NewBoard(workflow *Workflow, tasks []Task) *Board {
+	board := &Board{Workflow: workflow, Columns: make([]BoardColumn, len(workflow.Statuses))}
+	index := make(map[string]int, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		board.Columns[i] = BoardColumn{Status: status, Tasks: []Task{}}
+		index[status.Key] = i
+	}
+	for _, task := range tasks {
+		if i, ok := index[task.Status]; ok {
+			board.Columns[i].Tasks = append(board.Columns[i].Tasks, task)
+		}
+	}
+	return board
+}
@@ -1,12 +1,22 @@
 package models
 
-//estados validos para las tareas
+import "strings"
+
+//estados validos para las tareas: identificadores neutros, las etiquetas se traducen (status.<estado>)
 const (
-	TaskStatusPending    = "pendiente"
-	TaskStatusInProgress = "en progreso"
-	TaskStatusCompleted  = "completada"
+	TaskStatusPending    = "pending"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusCompleted  = "completed"
 )
 
+//Nombres anteriores en español, que se siguen aceptando al recibir un estado
+var legacyTaskStatuses = map[string]string{
+	"pendiente":   TaskStatusPending,
+	"en progreso": TaskStatusInProgress,
+	"en_progreso": TaskStatusInProgress,
+	"completada":  TaskStatusCompleted,
+}
+
 //Validaciones de longitud
 const (
 	TaskTitleMaxLength       = 200
@@ -35,3 +45,13 @@ func GetValidTasksStatuesList() []string {
 		TaskStatusCompleted,
 	}
 }
+
+//Normaliza un estado recibido: minúsculas, sin espacios alrededor y con los alias en español
+//traducidos a su identificador. Los estados desconocidos se devuelven tal cual para validarlos después
+func NormalizeTaskStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if canonical, ok := legacyTaskStatuses[status]; ok {
+		return canonical
+	}
+	return status
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// IdempotencyKey guarda la respuesta a una petición enviada con la cabecera Idempotency-Key
+// para devolverla tal cual si el cliente repite la petición
+type IdempotencyKey struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key    string `gorm:"column:idempotency_key;size:255;not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	// RequestHash identifica método, ruta y cuerpo: la misma clave no sirve para otra petición
+	RequestHash string `gorm:"size:64;not null"`
+	// StatusCode es 0 mientras la petición original está en curso
+	StatusCode   int
+	ContentType  string `gorm:"size:255"`
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	// LockedUntil es el plazo de la petición en curso: si vence sin respuesta, el proceso
+	// se cayó sin liberar la clave y un reintento puede reutilizarla. nil: sin plazo
+	LockedUntil *time.Time
+}
+
+// Completed indica si ya se guardó la respuesta de la petición original
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
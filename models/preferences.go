@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/i18n"
+)
+
+// representa los datos para cambiar las preferencias del usuario. Los campos omitidos no cambian
+type PreferencesRequest struct {
+	// Language vacío borra la preferencia y se vuelve a usar Accept-Language
+	Language *string `json:"language"`
+	// Timezone es un nombre IANA (Europe/Madrid). Vacío borra la preferencia y se usa UTC
+	Timezone *string `json:"timezone"`
+}
+
+// Validate exige al menos una preferencia y comprueba que el idioma esté soportado
+// y que la zona horaria exista
+func (r PreferencesRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Language == nil && r.Timezone == nil {
+		errs.add("language", "required", i18n.Msg("validation.required"))
+		errs.add("timezone", "required", i18n.Msg("validation.required"))
+	}
+	if r.Language != nil && *r.Language != "" {
+		if _, ok := i18n.Parse(*r.Language); !ok {
+			errs.add("language", "oneof", i18n.Msg("validation.oneof", strings.Join(SupportedLanguages(), ", ")))
+		}
+	}
+	if r.Timezone != nil && *r.Timezone != "" {
+		if _, err := time.LoadLocation(*r.Timezone); err != nil {
+			errs.add("timezone", "timezone", i18n.Msg("validation.timezone"))
+		}
+	}
+
+	return errs.err()
+}
+
+// representa las preferencias del usuario
+type PreferencesResponse struct {
+	Language           string   `json:"language"`
+	Timezone           string   `json:"timezone"`
+	SupportedLanguages []string `json:"supported_languages"`
+}
+
+// SupportedLanguages retorna los códigos de los idiomas soportados
+func SupportedLanguages() []string {
+	supported := i18n.Supported()
+	codes := make([]string, len(supported))
+	for i, locale := range supported {
+		codes[i] = string(locale)
+	}
+	return codes
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"go-task-manager-mvc/i18n"
+)
+
+// Agrupaciones de las series de tareas creadas y completadas
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// Rango de las series: por defecto los últimos 30 días y como máximo un año
+const (
+	StatsDefaultDays = 30
+	StatsMaxDays     = 366
+)
+
+// statsDateLayout es el formato de from, to y del inicio de cada periodo
+const statsDateLayout = "2006-01-02"
+
+// Grupos de fecha límite de las tareas sin completar, en el orden de StatsRange.DueBounds
+var StatsDueBuckets = []string{"overdue", "today", "tomorrow", "next_7_days", "later"}
+
+// StatsRequest son los parámetros de GET /api/stats
+type StatsRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Interval string `form:"interval"`
+	// Timezone es un nombre IANA (Europe/Madrid). Vacío: UTC (StatsService usa antes la del usuario)
+	Timezone string `form:"tz"`
+}
+
+// StatsRange es el rango de las estadísticas resuelto en la zona horaria del usuario
+type StatsRange struct {
+	Location *time.Location
+	Interval string
+	Now      time.Time
+	// Periods son los inicios de cada periodo seguidos del final del último
+	Periods []time.Time
+}
+
+// Resolve valida los parámetros y calcula los periodos a partir de now.
+// Los días empiezan a medianoche en la zona horaria indicada y las semanas en lunes
+func (r StatsRequest) Resolve(now time.Time) (*StatsRange, error) {
+	var errs ValidationErrors
+
+	location := time.UTC
+	if r.Timezone != "" {
+		loc, err := time.LoadLocation(r.Timezone)
+		if err != nil {
+			errs.add("tz", "timezone", i18n.Msg("validation.timezone"))
+		} else {
+			location = loc
+		}
+	}
+
+	interval := r.Interval
+	if interval == "" {
+		interval = StatsIntervalDay
+	} else if interval != StatsIntervalDay && interval != StatsIntervalWeek {
+		errs.add("interval", "oneof", i18n.Msg("validation.oneof", StatsIntervalDay+", "+StatsIntervalWeek))
+	}
+
+	now = now.In(location)
+	to := startOfDay(now)
+	if r.To != "" {
+		date, err := time.ParseInLocation(statsDateLayout, r.To, location)
+		if err != nil {
+			errs.add("to", "date", i18n.Msg("validation.date"))
+		}
+		to = date
+	}
+	from := to.AddDate(0, 0, -(StatsDefaultDays - 1))
+	if r.From != "" {
+		date, err := time.ParseInLocation(statsDateLayout, r.From, location)
+		if err != nil {
+			errs.add("from", "date", i18n.Msg("validation.date"))
+		}
+		from = date
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	// Redondear a horas descuenta los días de 23 o 25 horas de los cambios de horario
+	days := int(to.Sub(from).Round(time.Hour).Hours()/24) + 1
+	switch {
+	case days < 1:
+		errs.add("to", "range", i18n.Msg("validation.stats.to_before_from"))
+	case days > StatsMaxDays:
+		errs.add("from", "range", i18n.Msg("validation.stats.max_days", strconv.Itoa(StatsMaxDays)))
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	step := 1
+	if interval == StatsIntervalWeek {
+		step = 7
+		from = from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	}
+	statsRange := &StatsRange{Location: location, Interval: interval, Now: now}
+	start := from
+	for !start.After(to) {
+		statsRange.Periods = append(statsRange.Periods, start)
+		start = start.AddDate(0, 0, step)
+	}
+	statsRange.Periods = append(statsRange.Periods, start)
+	return statsRange, nil
+}
+
+// DueBounds separan los grupos de StatsDueBuckets: vencidas antes de ahora, hoy, mañana,
+// los 7 días siguientes a hoy y más adelante
+func (r *StatsRange) DueBounds() []time.Time {
+	today := startOfDay(r.Now)
+	return []time.Time{r.Now, today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), today.AddDate(0, 0, 8)}
+}
+
+// PeriodLabel retorna la fecha de inicio del periodo i (AAAA-MM-DD)
+func (r *StatsRange) PeriodLabel(i int) string {
+	return r.Periods[i].Format(statsDateLayout)
+}
+
+// Dates retorna el primer y el último día (incluido) de los periodos, que con interval=week
+// se amplían a semanas completas
+func (r *StatsRange) Dates() (from, to string) {
+	last := r.Periods[len(r.Periods)-1].AddDate(0, 0, -1)
+	return r.PeriodLabel(0), last.Format(statsDateLayout)
+}
+
+// startOfDay retorna la medianoche del día de t en su zona horaria
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// TaskStats resume la productividad de un usuario
+type TaskStats struct {
+	Workflow *Workflow
+	Range    *StatsRange
+	// ByStatus tiene los estados del flujo en orden, seguidos de los que el flujo ya no incluye
+	ByStatus  []StatusCount
+	Total     int64
+	Completed int64
+	// AverageCompletion (desde la creación) y AverageCycle (desde el inicio) son nil
+	// si no hay tareas completadas
+	AverageCompletion *time.Duration
+	AverageCycle      *time.Duration
+	// CreatedByPeriod y CompletedByPeriod tienen una entrada por periodo de Range
+	CreatedByPeriod   []int64
+	CompletedByPeriod []int64
+	// Due tiene una entrada por cada grupo de StatsDueBuckets
+	Due       []int64
+	NoDueDate int64
+}
+
+// StatusCount es el número de tareas de un usuario en un estado
+type StatusCount struct {
+	Status string
+	Count  int64
+}
+
+// CompletionRate retorna la fracción de tareas completadas, 0 si no hay tareas
+func (s *TaskStats) CompletionRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Completed) / float64(s.Total)
+}
+
+// Overdue retorna las tareas sin completar con la fecha límite vencida
+func (s *TaskStats) Overdue() int64 {
+	return s.Due[0]
+}
@@ -1,10 +1,12 @@
 package models
 
 import (
-	"errors"
+	"strconv"
 	"strings"
 	"time"
 
+	"go-task-manager-mvc/i18n"
+
 	"gorm.io/gorm"
 )
 
@@ -12,64 +14,85 @@ type Task struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Title       string         `gorm:"not null" json:"title"`
 	Description string         `json:"description"`
-	Status      string         `gorm:"default:'pendiente'" json:"status"`
+	Status      string         `gorm:"default:'pending'" json:"status"`
+	Position    int            `gorm:"not null;default:0" json:"position"` // orden dentro de la columna del tablero
 	DueDate     *time.Time     `json:"due_date"`
+	StartedAt   *time.Time     `json:"started_at"`   // cuándo salió del estado inicial del flujo
+	CompletedAt *time.Time     `json:"completed_at"` // cuándo entró en un estado completado
 	UserID      uint           `gorm:"not null;index" json:"user_id"`
 	User        User           `gorm:"foreignKey:UserID" json:"-"` // json:"-" evita que se serialice en las respuestas
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// workflow es el flujo del usuario con el que se valida el estado; nil usa el flujo por defecto
+	workflow *Workflow
+	// previousStatus es el estado de origen de la transición que valida Validate
+	previousStatus string
 }
 
-// BeforeSave hook de GORM para validar antes de guardar
-func (t *Task) BeforeSave(tx *gorm.DB) error {
-	return t.Validate()
+// UseWorkflow asocia la tarea al flujo de trabajo de su usuario. En las tareas ya guardadas el
+// estado actual queda como origen: Validate rechaza los cambios de estado que el flujo no permite
+func (t *Task) UseWorkflow(workflow *Workflow) {
+	t.workflow = workflow
+	if t.ID != 0 {
+		t.previousStatus = t.Status
+	}
 }
 
-// BeforeUpdate hook de GORM para validar antes de actualizar
-func (t *Task) BeforeUpdate(tx *gorm.DB) error {
-	return t.Validate()
+// Workflow retorna el flujo de trabajo de la tarea
+func (t *Task) Workflow() *Workflow {
+	if t.workflow == nil {
+		return defaultWorkflow
+	}
+	return t.workflow
 }
 
-// Validate valida los campos de la tarea
+// Validate valida los campos de la tarea. La llama TaskService antes de guardar.
+// Retorna ValidationErrors con todos los campos inválidos
 func (t *Task) Validate() error {
+	var errs ValidationErrors
+
 	// Validar título
 	t.Title = strings.TrimSpace(t.Title)
 	if t.Title == "" {
-		return errors.New("el título es obligatorio")
-	}
-	if len(t.Title) > TaskTitleMaxLength {
-		return errors.New("el título no puede exceder los 200 caracteres")
+		errs.add("title", "required", i18n.Msg("validation.required"))
+	} else if len(t.Title) > TaskTitleMaxLength {
+		errs.add("title", "max", i18n.Msg("validation.max.string", strconv.Itoa(TaskTitleMaxLength)))
 	}
 
 	// Validar descripción
 	t.Description = strings.TrimSpace(t.Description)
 	if len(t.Description) > TaskDescriptionMaxLength {
-		return errors.New("la descripción no puede exceder los 1000 caracteres")
+		errs.add("description", "max", i18n.Msg("validation.max.string", strconv.Itoa(TaskDescriptionMaxLength)))
 	}
 
-	// Validar estado
-	t.Status = strings.TrimSpace(strings.ToLower(t.Status))
+	// Validar estado y transición según el flujo de trabajo
+	workflow := t.Workflow()
+	t.Status = NormalizeTaskStatus(t.Status)
 	if t.Status == "" {
-		t.Status = TaskStatusPending
+		t.Status = workflow.Initial()
 	}
-	if !IsValidTaskStatus(t.Status) {
-		return errors.New("estado inválido. Use: pendiente, en progreso o completada")
+	switch {
+	case !workflow.Has(t.Status):
+		errs.add("status", "oneof", i18n.Msg("validation.oneof", strings.Join(workflow.Keys(), ", ")))
+	case t.previousStatus != "" && !workflow.CanTransition(t.previousStatus, t.Status):
+		errs.add("status", "transition", i18n.Msg("validation.transition", t.previousStatus, t.Status))
 	}
 
 	// Validar fecha límite (no puede ser en el pasado para tareas nuevas)
 	if t.DueDate != nil && t.ID == 0 { // Solo validar en creación
 		if t.DueDate.Before(time.Now()) {
-			return errors.New("la fecha límite no puede ser en el pasado")
+			errs.add("due_date", "past", i18n.Msg("validation.past"))
 		}
 	}
 
 	// Validar UserID
 	if t.UserID == 0 {
-		return errors.New("el usuario es obligatorio")
+		errs.add("user_id", "required", i18n.Msg("validation.required"))
 	}
 
-	return nil
+	return errs.err()
 }
 
 // IsOverdue verifica si la tarea está vencida
@@ -77,29 +100,82 @@ func (t *Task) IsOverdue() bool {
 	if t.DueDate == nil {
 		return false
 	}
-	return t.DueDate.Before(time.Now()) && t.Status != TaskStatusCompleted
+	return t.DueDate.Before(time.Now()) && !t.IsCompleted()
 }
 
-// IsCompleted verifica si la tarea está completada
+// IsCompleted verifica si la tarea está en un estado que su flujo cuenta como completado
 func (t *Task) IsCompleted() bool {
-	return t.Status == TaskStatusCompleted
+	return t.Workflow().IsDone(t.Status)
 }
 
-// MarkAsCompleted marca la tarea como completada
+// MarkAsCompleted marca la tarea como completada con el primer estado completado del flujo.
+// RecordStatusChange registra después la transición al guardarla
 func (t *Task) MarkAsCompleted() {
-	t.Status = TaskStatusCompleted
+	t.Status = t.Workflow().DoneStatus()
+	t.trackStatus(time.Now())
+}
+
+// RecordStatusChange actualiza StartedAt y CompletedAt si el estado cambió desde que se cargó
+// la tarea (o si es nueva) y retorna la transición para el registro de cambios de estado.
+// Retorna nil si el estado no cambió. La llama TaskService después de Validate
+func (t *Task) RecordStatusChange(now time.Time) *TaskStatusTransition {
+	if t.ID != 0 && t.Status == t.previousStatus {
+		return nil
+	}
+	transition := &TaskStatusTransition{
+		TaskID:    t.ID,
+		UserID:    t.UserID,
+		From:      t.previousStatus,
+		To:        t.Status,
+		CreatedAt: now,
+	}
+	t.trackStatus(now)
+	t.previousStatus = t.Status
+	return transition
+}
+
+// trackStatus mantiene StartedAt y CompletedAt según el estado actual:
+// volver al estado inicial borra StartedAt, salir de él lo fija la primera vez,
+// entrar en un estado completado fija CompletedAt y salir de ellos lo borra
+func (t *Task) trackStatus(now time.Time) {
+	workflow := t.Workflow()
+	if t.Status == workflow.Initial() {
+		t.StartedAt = nil
+	} else if t.StartedAt == nil {
+		t.StartedAt = &now
+	}
+
+	if !workflow.IsDone(t.Status) {
+		t.CompletedAt = nil
+	} else if t.CompletedAt == nil {
+		t.CompletedAt = &now
+	}
+}
+
+// LeadTime retorna el tiempo entre la creación y la finalización de la tarea; nil si no está completada
+func (t *Task) LeadTime() *time.Duration {
+	if t.CompletedAt == nil {
+		return nil
+	}
+	lead := t.CompletedAt.Sub(t.CreatedAt)
+	return &lead
+}
+
+// CycleTime retorna el tiempo entre el inicio y la finalización de la tarea; nil si no está completada
+func (t *Task) CycleTime() *time.Duration {
+	if t.CompletedAt == nil || t.StartedAt == nil {
+		return nil
+	}
+	cycle := t.CompletedAt.Sub(*t.StartedAt)
+	return &cycle
 }
 
 // GetStatusColor retorna un color para el estado (útil para frontend)
 func (t *Task) GetStatusColor() string {
-	switch t.Status {
-	case TaskStatusPending:
-		return "yellow"
-	case TaskStatusInProgress:
-		return "blue"
-	case TaskStatusCompleted:
-		return "green"
-	default:
-		return "gray"
-	}
+	return t.Workflow().Color(t.Status)
+}
+
+// StatusLabel retorna el nombre del estado en el idioma indicado
+func (t *Task) StatusLabel(locale i18n.Locale) string {
+	return t.Workflow().Label(locale, t.Status)
 }
@@ -1,34 +1,53 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// StatusInput es el estado recibido en una petición: al decodificarlo se traducen
+// los alias en español a su identificador, así la validación solo conoce los identificadores
+type StatusInput string
+
+func (s *StatusInput) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = StatusInput(NormalizeTaskStatus(raw))
+	return nil
+}
 
 // representa los datos para crear una tarea
 type TaskCreateRequest struct {
-	Title       string     `json:"title" binding:"required,min=3,max=200"`
-	Description string     `json:"description" binding:"max=1000"`
-	Status      string     `json:"status" binding:"omitempty,oneof=pendiente 'en progreso' completada"`
-	DueDate     *time.Time `json:"due_date"`
+	Title       string      `json:"title" binding:"required,min=3,max=200"`
+	Description string      `json:"description" binding:"max=1000"`
+	Status      StatusInput `json:"status" binding:"omitempty,max=64"` // lo valida Task.Validate con el flujo del usuario
+	DueDate     *time.Time  `json:"due_date"`
 }
 
 // representa los datos para actualizar una tarea
 type TaskUpdateRequest struct {
-	Title       string     `json:"title" binding:"omitempty,min=3,max=200"`
-	Description string     `json:"description" binding:"omitempty,max=1000"`
-	Status      string     `json:"status" binding:"omitempty,oneof=pendiente 'en progreso' completada"`
-	DueDate     *time.Time `json:"due_date"`
+	Title       string      `json:"title" binding:"omitempty,min=3,max=200"`
+	Description string      `json:"description" binding:"omitempty,max=1000"`
+	Status      StatusInput `json:"status" binding:"omitempty,max=64"` // lo valida Task.Validate con el flujo del usuario
+	DueDate     *time.Time  `json:"due_date"`
+}
+
+// representa el movimiento de una tarea en el tablero
+type TaskMoveRequest struct {
+	// Status vacío reordena la tarea dentro de su columna
+	Status StatusInput `json:"status" binding:"omitempty,max=64"`
+	// Position es la posición en la columna de destino, empezando en 0. Sin indicar: al final
+	Position *int `json:"position" binding:"omitempty,min=0"`
 }
 
 // convierte TaskCreateRequest a Task
 func (r *TaskCreateRequest) ToTask(userID uint) Task {
-	status := r.Status
-	if status == "" {
-		status = TaskStatusPending
-	}
-
 	return Task{
 		Title:       r.Title,
 		Description: r.Description,
-		Status:      status,
+		Status:      string(r.Status), // vacío: Validate usa el estado inicial del flujo
 		DueDate:     r.DueDate,
 		UserID:      userID,
 	}
@@ -43,7 +62,7 @@ func (r *TaskUpdateRequest) ApplyToTask(task *Task) {
 		task.Description = r.Description
 	}
 	if r.Status != "" {
-		task.Status = r.Status
+		task.Status = string(r.Status)
 	}
 	if r.DueDate != nil {
 		task.DueDate = r.DueDate
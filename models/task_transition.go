@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TaskStatusTransition registra un cambio de estado de una tarea. From está vacío
+// en la transición con la que se crea la tarea
+type TaskStatusTransition struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    uint      `gorm:"not null" json:"task_id"`
+	UserID    uint      `gorm:"not null" json:"-"`
+	From      string    `gorm:"column:from_status;size:64;not null" json:"from"`
+	To        string    `gorm:"column:to_status;size:64;not null" json:"to"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+
 	"gorm.io/gorm"
 )
 
@@ -8,6 +10,14 @@ type User struct {
 	gorm.Model
 	Username string `gorm:"unique;not null" json:"username"`
 	Email    string `gorm:"unique;not null" json:"email"`
-	Password string `gorm:"not null" json:"password"`
-	Tasks    []Task `gorm:"foreignKey:UserID" json:"tasks,omitempty"`
+	Password string `gorm:"not null" json:"-"`                           // nunca se serializa el hash de la contraseña
+	Language string `gorm:"size:8;not null;default:''" json:"language"`  // vacío: se usa Accept-Language
+	Timezone string `gorm:"size:64;not null;default:''" json:"timezone"` // vacío: las estadísticas usan UTC
+	Tasks    []Task `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// MarshalJSON serializa siempre al usuario mediante su DTO público,
+// de modo que ningún handler pueda exponer campos sensibles por accidente
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.ToResponse())
 }
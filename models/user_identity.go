@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserIdentity vincula un usuario local con una identidad de un proveedor OIDC
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"-"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	Issuer    string    `gorm:"size:255;not null;uniqueIndex:idx_identity_issuer_subject" json:"issuer"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_issuer_subject" json:"subject"`
+	Email     string    `gorm:"size:255" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// representa los datos públicos de un usuario
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Language  string    `json:"language"`
+	Timezone  string    `json:"timezone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// convierte User a UserResponse (sin contraseña ni relaciones)
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Language:  u.Language,
+		Timezone:  u.Timezone,
+		CreatedAt: u.CreatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+
+	"go-task-manager-mvc/i18n"
+)
+
+// FieldError es un error de validación de un campo concreto. El mensaje se traduce al responder
+type FieldError struct {
+	Field   string
+	Code    string
+	Message i18n.Message
+}
+
+func (e FieldError) Error() string { return e.Field + ": " + e.Message.String() }
+
+// ValidationErrors agrupa todos los campos inválidos de una entidad
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add registra un campo inválido
+func (e *ValidationErrors) add(field, code string, message i18n.Message) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// err retorna nil si no hay campos inválidos
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/i18n"
+)
+
+// Límites de un flujo de trabajo propio
+const (
+	WorkflowMaxStatuses     = 20
+	WorkflowStatusKeyMaxLen = 64
+)
+
+// Los identificadores de estado son estables y se usan en la API y en la base de datos
+var workflowStatusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// WorkflowStatus es un estado del flujo de trabajo de un usuario
+type WorkflowStatus struct {
+	ID     uint   `gorm:"primaryKey" json:"-"`
+	UserID uint   `gorm:"not null" json:"-"`
+	Key    string `gorm:"column:status_key;size:64;not null" json:"key"`
+	// Name es el nombre para mostrar. Vacío: se usa la traducción de status.<key>, o la clave
+	Name     string `gorm:"size:100;not null" json:"name"`
+	Color    string `gorm:"size:32;not null" json:"color"`
+	Position int    `gorm:"not null" json:"position"`
+	// Done indica que las tareas en este estado cuentan como completadas
+	Done bool `gorm:"column:is_done;not null" json:"done"`
+	// WIPLimit es el máximo de tareas en este estado; 0 es sin límite
+	WIPLimit  int       `gorm:"column:wip_limit;not null" json:"wip_limit"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// WorkflowTransition permite pasar una tarea del estado From al estado To
+type WorkflowTransition struct {
+	ID     uint   `gorm:"primaryKey" json:"-"`
+	UserID uint   `gorm:"not null" json:"-"`
+	From   string `gorm:"column:from_status;size:64;not null" json:"from"`
+	To     string `gorm:"column:to_status;size:64;not null" json:"to"`
+}
+
+// Workflow son los estados de las tareas de un usuario y las transiciones permitidas entre ellos.
+// Sin transiciones se puede pasar de cualquier estado a cualquier otro. Cada usuario es su propio
+// espacio de trabajo: no hay flujos compartidos por un equipo
+type Workflow struct {
+	Statuses    []WorkflowStatus
+	Transitions []WorkflowTransition
+	// Custom indica que el usuario definió su propio flujo
+	Custom bool
+}
+
+// defaultWorkflow es el flujo de las tareas que no tienen uno asociado
+var defaultWorkflow = DefaultWorkflow()
+
+// DefaultWorkflow retorna el flujo de los usuarios que no definieron uno propio
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: TaskStatusPending, Color: "yellow", Position: 0},
+			{Key: TaskStatusInProgress, Color: "blue", Position: 1},
+			{Key: TaskStatusCompleted, Color: "green", Position: 2, Done: true},
+		},
+	}
+}
+
+// Status busca un estado del flujo por su identificador
+func (w *Workflow) Status(key string) (WorkflowStatus, bool) {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// Has verifica si el estado pertenece al flujo
+func (w *Workflow) Has(key string) bool {
+	_, ok := w.Status(key)
+	return ok
+}
+
+// Keys retorna los identificadores de los estados en orden
+func (w *Workflow) Keys() []string {
+	keys := make([]string, len(w.Statuses))
+	for i, status := range w.Statuses {
+		keys[i] = status.Key
+	}
+	return keys
+}
+
+// DoneKeys retorna los identificadores de los estados que cuentan como completados
+func (w *Workflow) DoneKeys() []string {
+	var keys []string
+	for _, status := range w.Statuses {
+		if status.Done {
+			keys = append(keys, status.Key)
+		}
+	}
+	return keys
+}
+
+// IsDone verifica si el estado cuenta como completado
+func (w *Workflow) IsDone(key string) bool {
+	status, ok := w.Status(key)
+	return ok && status.Done
+}
+
+// Initial retorna el estado de las tareas nuevas que no indican uno: el primero del flujo
+func (w *Workflow) Initial() string {
+	if len(w.Statuses) == 0 {
+		return TaskStatusPending
+	}
+	return w.Statuses[0].Key
+}
+
+// DoneStatus retorna el primer estado que cuenta como completado
+func (w *Workflow) DoneStatus() string {
+	for _, status := range w.Statuses {
+		if status.Done {
+			return status.Key
+		}
+	}
+	return TaskStatusCompleted
+}
+
+// CanTransition verifica si una tarea puede pasar de from a to.
+// Quedarse en el mismo estado siempre está permitido, y también salir de un estado que
+// ya no pertenece al flujo para que las tareas antiguas no queden bloqueadas
+func (w *Workflow) CanTransition(from, to string) bool {
+	if from == to || len(w.Transitions) == 0 || !w.Has(from) {
+		return true
+	}
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Label retorna el nombre para mostrar del estado en el idioma indicado
+func (w *Workflow) Label(locale i18n.Locale, key string) string {
+	if status, ok := w.Status(key); ok && status.Name != "" {
+		return status.Name
+	}
+	if label := i18n.T(locale, "status."+key); label != "status."+key {
+		return label
+	}
+	return key
+}
+
+// WIPLimit retorna el máximo de tareas del estado, o 0 si no tiene límite
+func (w *Workflow) WIPLimit(key string) int {
+	status, _ := w.Status(key)
+	return status.WIPLimit
+}
+
+// Color retorna el color del estado (útil para frontend)
+func (w *Workflow) Color(key string) string {
+	if status, ok := w.Status(key); ok && status.Color != "" {
+		return status.Color
+	}
+	return "gray"
+}
+
+// Validate valida la definición del flujo: identificadores únicos con formato válido que no sean
+// alias en español, al menos un estado completado y transiciones entre estados del flujo
+func (w *Workflow) Validate() error {
+	var errs ValidationErrors
+
+	if len(w.Statuses) == 0 {
+		errs.add("statuses", "required", i18n.Msg("validation.required"))
+	} else if len(w.Statuses) > WorkflowMaxStatuses {
+		errs.add("statuses", "max", i18n.Msg("validation.max.list", strconv.Itoa(WorkflowMaxStatuses)))
+	}
+
+	seen := make(map[string]bool, len(w.Statuses))
+	hasDone := false
+	for i := range w.Statuses {
+		status := &w.Statuses[i]
+		field := fmt.Sprintf("statuses[%d].key", i)
+		status.Key = strings.TrimSpace(status.Key)
+		status.Name = strings.TrimSpace(status.Name)
+		status.Position = i
+
+		switch {
+		case status.Key == "":
+			errs.add(field, "required", i18n.Msg("validation.required"))
+		case len(status.Key) > WorkflowStatusKeyMaxLen:
+			errs.add(field, "max", i18n.Msg("validation.max.string", strconv.Itoa(WorkflowStatusKeyMaxLen)))
+		case !workflowStatusKeyPattern.MatchString(status.Key):
+			errs.add(field, "format", i18n.Msg("validation.status_key"))
+		case legacyTaskStatuses[status.Key] != "":
+			// NormalizeTaskStatus convertiría el alias en otro estado al recibirlo
+			errs.add(field, "reserved", i18n.Msg("validation.workflow.alias_key", legacyTaskStatuses[status.Key]))
+		case seen[status.Key]:
+			errs.add(field, "unique", i18n.Msg("validation.unique"))
+		}
+		if status.WIPLimit < 0 {
+			errs.add(fmt.Sprintf("statuses[%d].wip_limit", i), "min", i18n.Msg("validation.min.number", "0"))
+		}
+		seen[status.Key] = true
+		hasDone = hasDone || status.Done
+	}
+	if len(w.Statuses) > 0 && !hasDone {
+		errs.add("statuses", "done_required", i18n.Msg("validation.workflow.done_required"))
+	}
+
+	valid := strings.Join(w.Keys(), ", ")
+	for i, transition := range w.Transitions {
+		if !seen[transition.From] {
+			errs.add(fmt.Sprintf("transitions[%d].from", i), "oneof", i18n.Msg("validation.oneof", valid))
+		}
+		if !seen[transition.To] {
+			errs.add(fmt.Sprintf("transitions[%d].to", i), "oneof", i18n.Msg("validation.oneof", valid))
+		}
+	}
+
+	return errs.err()
+}
@@ -0,0 +1,92 @@
+package models
+
+import "go-task-manager-mvc/i18n"
+
+// representa un flujo de trabajo completo; sustituye al anterior
+type WorkflowRequest struct {
+	Statuses    []WorkflowStatusRequest     `json:"statuses" binding:"required,min=1,max=20,dive"`
+	Transitions []WorkflowTransitionRequest `json:"transitions" binding:"dive"`
+}
+
+// representa un estado del flujo. El orden de la lista es el orden de los estados
+type WorkflowStatusRequest struct {
+	Key   string `json:"key" binding:"required,max=64"`
+	Name  string `json:"name" binding:"max=100"`
+	Color string `json:"color" binding:"max=32"`
+	Done  bool   `json:"done"`
+	// WIPLimit limita las tareas en este estado; 0 o ausente es sin límite
+	WIPLimit int `json:"wip_limit" binding:"min=0"`
+}
+
+// representa una transición permitida entre dos estados
+type WorkflowTransitionRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// convierte WorkflowRequest al flujo del usuario, sin transiciones repetidas
+func (r *WorkflowRequest) ToWorkflow(userID uint) *Workflow {
+	workflow := &Workflow{Custom: true}
+	for i, status := range r.Statuses {
+		workflow.Statuses = append(workflow.Statuses, WorkflowStatus{
+			UserID:   userID,
+			Key:      status.Key,
+			Name:     status.Name,
+			Color:    status.Color,
+			Position: i,
+			Done:     status.Done,
+			WIPLimit: status.WIPLimit,
+		})
+	}
+
+	seen := make(map[WorkflowTransitionRequest]bool, len(r.Transitions))
+	for _, transition := range r.Transitions {
+		if seen[transition] {
+			continue
+		}
+		seen[transition] = true
+		workflow.Transitions = append(workflow.Transitions, WorkflowTransition{
+			UserID: userID,
+			From:   transition.From,
+			To:     transition.To,
+		})
+	}
+	return workflow
+}
+
+// representa un estado del flujo con su nombre en el idioma de la petición
+type WorkflowStatusResponse struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Color string `json:"color"`
+	Done  bool   `json:"done"`
+	// WIPLimit es 0 si el estado no tiene límite
+	WIPLimit int `json:"wip_limit"`
+}
+
+// representa el flujo de trabajo del usuario
+type WorkflowResponse struct {
+	Statuses    []WorkflowStatusResponse `json:"statuses"`
+	Transitions []WorkflowTransition     `json:"transitions"`
+	// Custom es false si el usuario usa el flujo por defecto
+	Custom bool `json:"custom"`
+}
+
+// convierte el flujo a WorkflowResponse con los nombres en el idioma indicado
+func (w *Workflow) ToResponse(locale i18n.Locale) WorkflowResponse {
+	response := WorkflowResponse{
+		Statuses:    make([]WorkflowStatusResponse, len(w.Statuses)),
+		Transitions: append([]WorkflowTransition{}, w.Transitions...),
+		Custom:      w.Custom,
+	}
+	for i, status := range w.Statuses {
+		response.Statuses[i] = WorkflowStatusResponse{
+			Key:      status.Key,
+			Label:    w.Label(locale, status.Key),
+			Color:    w.Color(status.Key),
+			Done:     status.Done,
+			WIPLimit: status.WIPLimit,
+		}
+	}
+	return response
+}
@@ -0,0 +1,103 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/i18n"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Los errores de validación usan el nombre JSON de los campos, que es el que conoce el cliente
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// Validation crea el error de validación con los campos indicados
+func Validation(errs ...FieldError) *Problem {
+	return New(http.StatusBadRequest, CodeValidationFailed, "error.invalid_fields").WithErrors(errs...)
+}
+
+// Binding traduce el error de ShouldBindJSON sin exponer los mensajes internos del decodificador
+func Binding(err error) *Problem {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			fields[i] = Field(fieldName(fe), fe.Tag(), tagMessage(fe))
+		}
+		return Validation(fields...)
+	case errors.As(err, &typeErr):
+		return Validation(Field(typeErr.Field, "type", i18n.Msg("validation.type."+kindName(typeErr.Type.Kind()))))
+	case errors.As(err, &timeErr):
+		return New(http.StatusBadRequest, CodeInvalidBody, "error.body_date")
+	case errors.Is(err, io.EOF):
+		return New(http.StatusBadRequest, CodeInvalidBody, "error.body_missing")
+	default:
+		return New(http.StatusBadRequest, CodeInvalidBody, "error.body_malformed")
+	}
+}
+
+// fieldName quita el nombre del struct: scopes[0] en lugar de APIKeyCreateRequest.scopes[0]
+func fieldName(fe validator.FieldError) string {
+	if _, field, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return field
+	}
+	return fe.Field()
+}
+
+func tagMessage(fe validator.FieldError) i18n.Message {
+	switch fe.Tag() {
+	case "required", "email":
+		return i18n.Msg("validation." + fe.Tag())
+	case "oneof":
+		// Mismo formato que el resto de listas de valores: "a, b, c"
+		return i18n.Msg("validation.oneof", strings.Join(strings.Fields(fe.Param()), ", "))
+	case "min", "max":
+		// El límite se refiere a la longitud de textos y listas y al valor de los números
+		return i18n.Msg("validation."+fe.Tag()+"."+sizeKind(fe.Kind()), fe.Param())
+	}
+	return i18n.Msg("validation.invalid")
+}
+
+func sizeKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "list"
+	}
+	return "number"
+}
+
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return "number"
+}
@@ -0,0 +1,54 @@
+package problem
+
+// Code identifica el tipo de error. Es estable: los clientes deben decidir por el código, no por el texto
+type Code string
+
+// Peticiones inválidas
+const (
+	CodeInvalidBody           Code = "invalid_body"
+	CodeValidationFailed      Code = "validation_failed"
+	CodeWeakPassword          Code = "weak_password"
+	CodeUsernameTaken         Code = "username_taken"
+	CodeEmailTaken            Code = "email_taken"
+	CodeInvalidIdempotencyKey Code = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused  Code = "idempotency_key_reused"
+	CodeIdempotencyInProgress Code = "idempotency_in_progress"
+	CodeRateLimited           Code = "rate_limited"
+	CodeStatusInUse           Code = "status_in_use"
+	CodeWIPLimitExceeded      Code = "wip_limit_exceeded"
+)
+
+// Autenticación y permisos
+const (
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeInvalidToken       Code = "invalid_token"
+	CodeSessionRevoked     Code = "session_revoked"
+	CodeInvalidAPIKey      Code = "invalid_api_key"
+	CodeInsufficientScope  Code = "insufficient_scope"
+)
+
+// Login con proveedor de identidad
+const (
+	CodeOIDCDisabled      Code = "oidc_disabled"
+	CodeOIDCRejected      Code = "oidc_rejected"
+	CodeOIDCInvalidState  Code = "oidc_invalid_state"
+	CodeOIDCMissingCode   Code = "oidc_missing_code"
+	CodeOIDCInvalidToken  Code = "oidc_invalid_token"
+	CodeOIDCUserNotLinked Code = "oidc_user_not_linked"
+)
+
+// Recursos no encontrados
+const (
+	CodeRouteNotFound   Code = "route_not_found"
+	CodeTaskNotFound    Code = "task_not_found"
+	CodeAPIKeyNotFound  Code = "api_key_not_found"
+	CodeSessionNotFound Code = "session_not_found"
+)
+
+// Errores del servidor
+const (
+	CodeInternal           Code = "internal_error"
+	CodeDatabaseTimeout    Code = "database_timeout"
+	CodeServiceUnavailable Code = "service_unavailable"
+)
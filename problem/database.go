@@ -0,0 +1,25 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-task-manager-mvc/dberrors"
+)
+
+// Segundos que se sugiere esperar al cliente cuando la base de datos no está disponible
+const databaseRetryAfter = 5
+
+// Unavailable traduce los errores de disponibilidad de la base de datos: 504 si la consulta
+// no respondió a tiempo y 503 con Retry-After si no hay conexión. Retorna nil si err no es de ese tipo
+func Unavailable(err error) *Problem {
+	switch {
+	case errors.Is(err, dberrors.ErrQueryTimeout):
+		return New(http.StatusGatewayTimeout, CodeDatabaseTimeout, "error.database_timeout")
+	case errors.Is(err, dberrors.ErrUnavailable):
+		return New(http.StatusServiceUnavailable, CodeServiceUnavailable, "error.service_unavailable").
+			WithHeader("Retry-After", strconv.Itoa(databaseRetryAfter))
+	}
+	return nil
+}
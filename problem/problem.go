@@ -0,0 +1,140 @@
+package problem
+
+import (
+	"encoding/json"
+
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaType es el tipo de contenido de los errores de la API (RFC 7807)
+const MediaType = "application/problem+json"
+
+// Prefijo del campo type: cada código tiene un tipo propio y estable
+const typePrefix = "urn:task-api:problem:"
+
+// Problem es el cuerpo de todas las respuestas de error de la API.
+// Title, Detail y los mensajes de Errors se traducen al idioma de la petición al responder;
+// Type y Code no dependen del idioma
+type Problem struct {
+	Type      string
+	Title     string
+	Status    int
+	Detail    string
+	Instance  string
+	Code      Code
+	RequestID string
+	Errors    []FieldError
+	// Extensions son miembros adicionales propios de cada error, como retry_after
+	Extensions map[string]any
+
+	detail i18n.Message
+	header map[string]string
+}
+
+// FieldError describe un campo del cuerpo de la petición que no es válido
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	message i18n.Message
+}
+
+// Field crea el error de un campo con un mensaje pendiente de traducir
+func Field(field, code string, message i18n.Message) FieldError {
+	return FieldError{Field: field, Code: code, message: message}
+}
+
+// New crea un error con el código dado. key es la clave del detalle en el catálogo de mensajes
+func New(status int, code Code, key string, args ...any) *Problem {
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Status: status,
+		Code:   code,
+		detail: i18n.Msg(key, args...),
+	}
+}
+
+// With añade un miembro adicional al error
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// WithHeader añade una cabecera a la respuesta del error
+func (p *Problem) WithHeader(key, value string) *Problem {
+	if p.header == nil {
+		p.header = make(map[string]string)
+	}
+	p.header[key] = value
+	return p
+}
+
+// WithErrors añade errores de campos concretos
+func (p *Problem) WithErrors(errs ...FieldError) *Problem {
+	p.Errors = append(p.Errors, errs...)
+	return p
+}
+
+// Localize traduce el título, el detalle y los mensajes de los campos
+func (p *Problem) Localize(locale i18n.Locale) {
+	p.Title = i18n.T(locale, "problem."+string(p.Code))
+	if p.detail.Key != "" {
+		p.Detail = p.detail.In(locale)
+	}
+	for i := range p.Errors {
+		if p.Errors[i].message.Key != "" {
+			p.Errors[i].Message = p.Errors[i].message.In(locale)
+		}
+	}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	body := make(map[string]any, len(p.Extensions)+8)
+	for key, value := range p.Extensions {
+		body[key] = value
+	}
+	body["type"] = p.Type
+	body["title"] = p.Title
+	body["status"] = p.Status
+	body["code"] = p.Code
+	if p.Detail != "" {
+		body["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		body["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		body["request_id"] = p.RequestID
+	}
+	if len(p.Errors) > 0 {
+		body["errors"] = p.Errors
+	}
+	return json.Marshal(body)
+}
+
+// Respond responde el error en el idioma de la petición, con la ruta y el ID de la petición
+// para poder encontrarla en los logs
+func Respond(c *gin.Context, p *Problem) {
+	ctx := c.Request.Context()
+	p.Localize(i18n.FromContext(ctx))
+	p.Instance = c.Request.URL.Path
+	p.RequestID = logging.RequestID(ctx)
+	for key, value := range p.header {
+		c.Header(key, value)
+	}
+	c.Header("Content-Type", MediaType)
+	c.JSON(p.Status, p)
+}
+
+// Abort responde el error y corta la cadena de middlewares
+func Abort(c *gin.Context, p *Problem) {
+	Respond(c, p)
+	c.Abort()
+}
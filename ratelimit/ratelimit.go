@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit es un token bucket con capacidad para Requests peticiones que se recarga
+// por completo en Period: permite ráfagas de Requests y un ritmo sostenido de Requests/Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// interval es el tiempo que tarda en recargarse un token
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result es el estado del bucket después de una petición
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter es lo que falta para el próximo token si la petición se rechazó
+	RetryAfter time.Duration
+	// Reset es lo que falta para que el bucket vuelva a estar lleno
+	Reset time.Duration
+}
+
+// Store guarda los buckets de cada clave. MemoryStore sirve para una sola instancia de la API;
+// con varias instancias detrás de un balanceador se necesita un store compartido (Redis, por ejemplo)
+// para que la cuota sea global
+type Store interface {
+	// Take intenta consumir un token del bucket de key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
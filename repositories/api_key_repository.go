@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository define el acceso a las API keys
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey *models.APIKey) error
+	// FindByPrefix busca una clave por su prefijo público, incluyendo su usuario
+	FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	FindByUser(ctx context.Context, userID uint) ([]models.APIKey, error)
+	FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.APIKey, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type gormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormAPIKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) error {
+	return translateError(writer(r.db, ctx).Create(apiKey).Error)
+}
+
+func (r *gormAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := r.db.WithContext(ctx).Preload("User").Where("prefix = ?", prefix).First(&apiKey).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &apiKey, nil
+}
+
+func (r *gormAPIKeyRepository) FindByUser(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	var apiKeys []models.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys).Error
+	return apiKeys, translateError(err)
+}
+
+func (r *gormAPIKeyRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&apiKey).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &apiKey, nil
+}
+
+func (r *gormAPIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	err := writer(r.db, ctx).Model(&models.APIKey{}).Where("id = ?", id).Update("revoked_at", at).Error
+	return translateError(err)
+}
+
+// TouchLastUsed registra el último uso sin pasar por los hooks del modelo
+func (r *gormAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	err := writer(r.db, ctx).Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+	return translateError(err)
+}
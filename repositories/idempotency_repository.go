@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository define el acceso a las claves de idempotencia
+type IdempotencyRepository interface {
+	// Create registra la clave como en curso. Retorna ErrDuplicate si el usuario ya la usó
+	Create(ctx context.Context, key *models.IdempotencyKey) error
+	FindByKey(ctx context.Context, userID uint, key string) (*models.IdempotencyKey, error)
+	// ReplaceExpired reutiliza para una petición nueva una clave expirada o una petición en curso
+	// con el plazo vencido. Retorna false si la clave sigue vigente
+	ReplaceExpired(ctx context.Context, key *models.IdempotencyKey, now time.Time) (bool, error)
+	// Complete guarda la respuesta de la petición original
+	Complete(ctx context.Context, id uint, status int, contentType string, body []byte) error
+	Delete(ctx context.Context, id uint) error
+	// DeleteExpired elimina las claves que expiraron antes de la fecha indicada
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type gormIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormIdempotencyRepository) Create(ctx context.Context, key *models.IdempotencyKey) error {
+	// Una clave repetida es lo esperado en un reintento: no se trata como error de la base de datos
+	result := writer(r.db, ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+func (r *gormIdempotencyRepository) FindByKey(ctx context.Context, userID uint, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &record, nil
+}
+
+func (r *gormIdempotencyRepository) ReplaceExpired(ctx context.Context, key *models.IdempotencyKey, now time.Time) (bool, error) {
+	// Las condiciones sobre expires_at y locked_until evitan que dos reintentos simultáneos reutilicen la misma clave
+	result := writer(r.db, ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND idempotency_key = ?", key.UserID, key.Key).
+		Where("expires_at <= ? OR (status_code = 0 AND locked_until <= ?)", now, now).
+		Updates(map[string]interface{}{
+			"request_hash":  key.RequestHash,
+			"status_code":   0,
+			"content_type":  "",
+			"response_body": nil,
+			"created_at":    now,
+			"expires_at":    key.ExpiresAt,
+			"locked_until":  key.LockedUntil,
+		})
+	if result.Error != nil {
+		return false, translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	replaced, err := r.FindByKey(ctx, key.UserID, key.Key)
+	if err != nil {
+		return false, err
+	}
+	*key = *replaced
+	return true, nil
+}
+
+func (r *gormIdempotencyRepository) Complete(ctx context.Context, id uint, status int, contentType string, body []byte) error {
+	return translateError(writer(r.db, ctx).Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code":   status,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error)
+}
+
+func (r *gormIdempotencyRepository) Delete(ctx context.Context, id uint) error {
+	return translateError(writer(r.db, ctx).Delete(&models.IdempotencyKey{}, id).Error)
+}
+
+func (r *gormIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := writer(r.db, ctx).Where("expires_at <= ?", before).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, translateError(result.Error)
+}
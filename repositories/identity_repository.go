@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+)
+
+// IdentityRepository define el acceso a las identidades de proveedores OIDC
+type IdentityRepository interface {
+	// FindByIssuerAndSubject busca una identidad incluyendo su usuario
+	FindByIssuerAndSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error)
+	Create(ctx context.Context, identity *models.UserIdentity) error
+}
+
+type gormIdentityRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormIdentityRepository) FindByIssuerAndSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).Preload("User").Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &identity, nil
+}
+
+func (r *gormIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return translateError(writer(r.db, ctx).Create(identity).Error)
+}
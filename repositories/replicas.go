@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// ReplicaSource elige la réplica en la que ejecutar una lectura
+type ReplicaSource interface {
+	// Replica retorna una réplica sana o nil si no hay ninguna disponible
+	Replica() *gorm.DB
+	// MarkUnhealthy deja de usar una réplica que falló al conectar
+	MarkUnhealthy(db *gorm.DB)
+}
+
+type writesKey struct{}
+
+// TrackWrites prepara un contexto para registrar las escrituras hechas con él.
+// Después de la primera, las lecturas con ese contexto van al primario y ven lo escrito
+func TrackWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writesKey{}, new(atomic.Bool))
+}
+
+// markWrite registra una escritura en ctx (si se preparó con TrackWrites)
+func markWrite(ctx context.Context) {
+	if written, ok := ctx.Value(writesKey{}).(*atomic.Bool); ok {
+		written.Store(true)
+	}
+}
+
+func hasWritten(ctx context.Context) bool {
+	written, ok := ctx.Value(writesKey{}).(*atomic.Bool)
+	return ok && written.Load()
+}
+
+// writer retorna la conexión para una escritura y la registra en ctx
+func writer(db *gorm.DB, ctx context.Context) *gorm.DB {
+	markWrite(ctx)
+	return db.WithContext(ctx)
+}
+
+// readRouter ejecuta las lecturas que toleran algo de retraso de replicación
+// en una réplica, con el primario como respaldo
+type readRouter struct {
+	primary  *gorm.DB
+	replicas ReplicaSource
+}
+
+// read ejecuta fn en una réplica salvo que ya se haya escrito con ctx o no haya réplicas sanas.
+// Si la réplica no responde se retira y la consulta se repite en el primario
+func (r readRouter) read(ctx context.Context, fn func(db *gorm.DB) error) error {
+	if r.replicas != nil && !hasWritten(ctx) {
+		if replica := r.replicas.Replica(); replica != nil {
+			err := fn(replica.WithContext(ctx))
+			if !isConnectionError(err) {
+				return translateError(err)
+			}
+			r.replicas.MarkUnhealthy(replica)
+		}
+	}
+	return translateError(fn(r.primary.WithContext(ctx)))
+}
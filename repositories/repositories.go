@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"go-task-manager-mvc/dberrors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound se retorna cuando el registro buscado no existe (o no pertenece al usuario)
+	ErrNotFound = errors.New("registro no encontrado")
+	// ErrQueryTimeout indica que la consulta no terminó antes del plazo de la petición
+	ErrQueryTimeout = dberrors.ErrQueryTimeout
+	// ErrDatabaseUnavailable indica que no se pudo conectar con la base de datos
+	ErrDatabaseUnavailable = dberrors.ErrUnavailable
+	// ErrDuplicate indica que el registro viola una restricción de unicidad
+	ErrDuplicate = errors.New("el registro ya existe")
+)
+
+// Repositories agrupa el acceso a datos de la aplicación
+type Repositories struct {
+	Users       UserRepository
+	Tasks       TaskRepository
+	APIKeys     APIKeyRepository
+	Sessions    SessionRepository
+	Identities  IdentityRepository
+	Idempotency IdempotencyRepository
+	Workflows   WorkflowRepository
+	Tx          Transactor
+}
+
+// Transactor ejecuta varias operaciones de forma atómica.
+// fn recibe repositorios que operan dentro de la transacción
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// NewGormRepositories crea los repositorios respaldados por GORM
+func NewGormRepositories(db *gorm.DB) Repositories {
+	return NewGormRepositoriesWithReplicas(db, nil)
+}
+
+// NewGormRepositoriesWithReplicas crea los repositorios enviando las listas de tareas a las réplicas.
+// Las sesiones y API keys se leen siempre del primario: con retraso de replicación
+// una credencial recién revocada parecería activa
+func NewGormRepositoriesWithReplicas(db *gorm.DB, replicas ReplicaSource) Repositories {
+	return Repositories{
+		Users:       &gormUserRepository{db: db},
+		Tasks:       &gormTaskRepository{db: db, reads: readRouter{primary: db, replicas: replicas}},
+		APIKeys:     &gormAPIKeyRepository{db: db},
+		Sessions:    &gormSessionRepository{db: db},
+		Identities:  &gormIdentityRepository{db: db},
+		Idempotency: &gormIdempotencyRepository{db: db},
+		Workflows:   &gormWorkflowRepository{db: db},
+		Tx:          &gormTransactor{db: db},
+	}
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	return translateError(writer(t.db, ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewGormRepositories(tx))
+	}))
+}
+
+// translateError convierte los errores de GORM y del driver en los errores del paquete
+func translateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrQueryTimeout), errors.Is(err, ErrDatabaseUnavailable), errors.Is(err, ErrDuplicate):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	case isConnectionError(err):
+		return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
+	case isDuplicateError(err):
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	}
+	return err
+}
+
+// isDuplicateError detecta la violación de un índice único (error 1062 de MySQL)
+func isDuplicateError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// isConnectionError detecta errores de red o de conexión con MySQL
+func isConnectionError(err error) bool {
+	var netErr *net.OpError
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case errors.As(err, &netErr),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, mysql.ErrInvalidConn),
+		errors.Is(err, sql.ErrConnDone):
+		return true
+	case errors.As(err, &mysqlErr):
+		// 1040: too many connections
+		return mysqlErr.Number == 1040
+	}
+	return false
+}
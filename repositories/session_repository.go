@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository define el acceso a las sesiones de login
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	// FindByID busca una sesión incluyendo su usuario
+	FindByID(ctx context.Context, id uint) (*models.Session, error)
+	FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Session, error)
+	// FindActiveByUser retorna las sesiones no revocadas ni expiradas, la más activa primero
+	FindActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	// RevokeAllByUser revoca todas las sesiones del usuario salvo exceptID (0 para ninguna)
+	RevokeAllByUser(ctx context.Context, userID uint, exceptID uint, at time.Time) (int64, error)
+	Touch(ctx context.Context, id uint, at time.Time) error
+	// DeleteInactive elimina las sesiones expiradas o revocadas antes de la fecha indicada
+	DeleteInactive(ctx context.Context, before time.Time) (int64, error)
+}
+
+type gormSessionRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return translateError(writer(r.db, ctx).Create(session).Error)
+}
+
+func (r *gormSessionRepository) FindByID(ctx context.Context, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Preload("User").First(&session, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) FindActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, translateError(err)
+}
+
+func (r *gormSessionRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	err := writer(r.db, ctx).Model(&models.Session{}).Where("id = ?", id).Update("revoked_at", at).Error
+	return translateError(err)
+}
+
+func (r *gormSessionRepository) RevokeAllByUser(ctx context.Context, userID uint, exceptID uint, at time.Time) (int64, error) {
+	query := writer(r.db, ctx).Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	result := query.Update("revoked_at", at)
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// Touch actualiza la última actividad sin pasar por los hooks del modelo
+func (r *gormSessionRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	err := writer(r.db, ctx).Model(&models.Session{}).Where("id = ?", id).UpdateColumn("last_seen_at", at).Error
+	return translateError(err)
+}
+
+func (r *gormSessionRepository) DeleteInactive(ctx context.Context, before time.Time) (int64, error) {
+	result := writer(r.db, ctx).Where("expires_at < ? OR revoked_at < ?", before, before).Delete(&models.Session{})
+	return result.RowsAffected, translateError(result.Error)
+}
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskRepository define el acceso a las tareas
+type TaskRepository interface {
+	// FindByUser retorna las tareas del usuario, más recientes primero.
+	// Si status no está vacío solo retorna las tareas con ese estado.
+	// Puede leer de una réplica (ver NewGormRepositoriesWithReplicas)
+	FindByUser(ctx context.Context, userID uint, status string) ([]models.Task, error)
+	// FindByIDForUser busca una tarea que pertenezca al usuario
+	FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Task, error)
+	Create(ctx context.Context, task *models.Task) error
+	Update(ctx context.Context, task *models.Task) error
+	Delete(ctx context.Context, task *models.Task) error
+	// FindColumn retorna las tareas del usuario en un estado ordenadas por posición.
+	// Bloquea las filas que encuentra hasta el final de la transacción (SELECT ... FOR UPDATE);
+	// en una columna vacía no bloquea nada, por eso quien la modifica bloquea antes al usuario (UserRepository.Lock)
+	FindColumn(ctx context.Context, userID uint, status string) ([]models.Task, error)
+	// UpdatePosition cambia solo la posición de una tarea
+	UpdatePosition(ctx context.Context, id uint, position int) error
+	// RecordTransition guarda un cambio de estado en el registro de la tarea
+	RecordTransition(ctx context.Context, transition *models.TaskStatusTransition) error
+	// FindTransitions retorna los cambios de estado de la tarea, del más antiguo al más reciente
+	FindTransitions(ctx context.Context, taskID uint) ([]models.TaskStatusTransition, error)
+	// StatusesInUse retorna los estados distintos de las tareas del usuario
+	StatusesInUse(ctx context.Context, userID uint) ([]string, error)
+	// SyncStatusTimes ajusta started_at y completed_at de las tareas del usuario a un flujo nuevo:
+	// sin inicio en el estado inicial, sin finalización fuera de los estados done y now donde falten
+	SyncStatusTimes(ctx context.Context, userID uint, initial string, done []string, now time.Time) error
+	// CountAll cuenta las tareas de todos los usuarios por estado y las vencidas sin completar.
+	// Los estados de los flujos propios se agrupan en OtherTaskStatus. Puede leer de una réplica
+	CountAll(ctx context.Context, now time.Time) (TaskCounts, error)
+	// Stats calcula los agregados de las tareas de un usuario. Puede leer de una réplica
+	Stats(ctx context.Context, query TaskStatsQuery) (TaskStats, error)
+}
+
+// OtherTaskStatus agrupa en TaskCounts los estados que no son los del flujo por defecto,
+// así las métricas tienen un número fijo de series aunque cada usuario defina los suyos
+const OtherTaskStatus = "other"
+
+// TaskCounts resume las tareas de todos los usuarios
+type TaskCounts struct {
+	ByStatus map[string]int64
+	Overdue  int64
+}
+
+// TaskStatsQuery indica qué agregados calcular. Los límites de los periodos se calculan
+// en la zona horaria del usuario, así la base de datos solo compara instantes
+type TaskStatsQuery struct {
+	UserID uint
+	// DoneStatuses son los estados que cuentan como completados en el flujo del usuario
+	DoneStatuses []string
+	// Periods son los inicios de cada periodo seguidos del final del último
+	Periods []time.Time
+	// DueBounds separan las fechas límite de las tareas sin completar en len(DueBounds)+1 grupos
+	DueBounds []time.Time
+}
+
+// TaskStats son los agregados de las tareas de un usuario
+type TaskStats struct {
+	ByStatus map[string]int64
+	// AvgCompletionSeconds es el tiempo medio entre la creación y la finalización
+	// de las tareas completadas; nil si no hay ninguna
+	AvgCompletionSeconds *float64
+	// AvgCycleSeconds es el tiempo medio entre el inicio y la finalización; nil si no hay ninguna
+	AvgCycleSeconds *float64
+	// Created y Completed tienen una entrada por periodo
+	Created   []int64
+	Completed []int64
+	// Due tiene len(DueBounds)+1 entradas; las tareas sin fecha límite cuentan en NoDueDate
+	Due       []int64
+	NoDueDate int64
+}
+
+// newTaskStats prepara los contadores de los periodos y grupos de la consulta
+func newTaskStats(query TaskStatsQuery) TaskStats {
+	periods := max(len(query.Periods)-1, 0)
+	return TaskStats{
+		ByStatus:  make(map[string]int64),
+		Created:   make([]int64, periods),
+		Completed: make([]int64, periods),
+		Due:       make([]int64, len(query.DueBounds)+1),
+	}
+}
+
+type gormTaskRepository struct {
+	db    *gorm.DB
+	reads readRouter
+}
+
+func (r *gormTaskRepository) FindByUser(ctx context.Context, userID uint, status string) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.reads.read(ctx, func(db *gorm.DB) error {
+		tasks = nil
+		query := db.Where("user_id = ?", userID)
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query.Order("created_at DESC").Find(&tasks).Error
+	})
+	return tasks, err
+}
+
+func (r *gormTaskRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Task, error) {
+	var task models.Task
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&task).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &task, nil
+}
+
+func (r *gormTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	return translateError(writer(r.db, ctx).Create(task).Error)
+}
+
+func (r *gormTaskRepository) Update(ctx context.Context, task *models.Task) error {
+	return translateError(writer(r.db, ctx).Save(task).Error)
+}
+
+// Delete realiza un soft delete
+func (r *gormTaskRepository) Delete(ctx context.Context, task *models.Task) error {
+	return translateError(writer(r.db, ctx).Delete(task).Error)
+}
+
+func (r *gormTaskRepository) FindColumn(ctx context.Context, userID uint, status string) ([]models.Task, error) {
+	var tasks []models.Task
+	err := writer(r.db, ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND status = ?", userID, status).
+		Order("position, id").
+		Find(&tasks).Error
+	return tasks, translateError(err)
+}
+
+func (r *gormTaskRepository) UpdatePosition(ctx context.Context, id uint, position int) error {
+	err := writer(r.db, ctx).Model(&models.Task{}).Where("id = ?", id).UpdateColumn("position", position).Error
+	return translateError(err)
+}
+
+func (r *gormTaskRepository) RecordTransition(ctx context.Context, transition *models.TaskStatusTransition) error {
+	return translateError(writer(r.db, ctx).Create(transition).Error)
+}
+
+func (r *gormTaskRepository) FindTransitions(ctx context.Context, taskID uint) ([]models.TaskStatusTransition, error) {
+	var transitions []models.TaskStatusTransition
+	err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Order("created_at, id").Find(&transitions).Error
+	return transitions, translateError(err)
+}
+
+func (r *gormTaskRepository) StatusesInUse(ctx context.Context, userID uint) ([]string, error) {
+	var statuses []string
+	err := r.db.WithContext(ctx).Model(&models.Task{}).Where("user_id = ?", userID).Distinct().Pluck("status", &statuses).Error
+	return statuses, translateError(err)
+}
+
+func (r *gormTaskRepository) SyncStatusTimes(ctx context.Context, userID uint, initial string, done []string, now time.Time) error {
+	updates := []struct {
+		where  string
+		status any
+		column string
+		value  any
+	}{
+		{"status = ? AND started_at IS NOT NULL", initial, "started_at", nil},
+		{"status <> ? AND started_at IS NULL", initial, "started_at", now},
+		{"status NOT IN ? AND completed_at IS NOT NULL", done, "completed_at", nil},
+		{"status IN ? AND completed_at IS NULL", done, "completed_at", now},
+	}
+	for _, update := range updates {
+		// UpdateColumn no cambia updated_at: las tareas no se modificaron, solo el flujo
+		err := writer(r.db, ctx).Model(&models.Task{}).
+			Where("user_id = ?", userID).Where(update.where, update.status).
+			UpdateColumn(update.column, update.value).Error
+		if err != nil {
+			return translateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *gormTaskRepository) CountAll(ctx context.Context, now time.Time) (TaskCounts, error) {
+	var counts TaskCounts
+	err := r.reads.read(ctx, func(db *gorm.DB) error {
+		var rows []struct {
+			StatusGroup string
+			Count       int64
+		}
+		if err := db.Model(&models.Task{}).
+			Select("CASE WHEN status IN ? THEN status ELSE ? END AS status_group, COUNT(*) AS count", models.GetValidTasksStatuesList(), OtherTaskStatus).
+			Group("status_group").Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		counts = TaskCounts{ByStatus: make(map[string]int64, len(rows))}
+		for _, row := range rows {
+			counts.ByStatus[row.StatusGroup] = row.Count
+		}
+		// Una tarea está completada si su estado es "done" en el flujo de su usuario,
+		// o si es completed y el usuario usa el flujo por defecto
+		return db.Model(&models.Task{}).
+			Where("due_date < ?", now).
+			Where("NOT EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.user_id = tasks.user_id AND ws.status_key = tasks.status AND ws.is_done)").
+			Where("(status <> ? OR EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.user_id = tasks.user_id))", models.TaskStatusCompleted).
+			Count(&counts.Overdue).Error
+	})
+	return counts, err
+}
+
+func (r *gormTaskRepository) Stats(ctx context.Context, query TaskStatsQuery) (TaskStats, error) {
+	var stats TaskStats
+	err := r.reads.read(ctx, func(db *gorm.DB) error {
+		stats = newTaskStats(query)
+		tasks := func() *gorm.DB { return db.Model(&models.Task{}).Where("user_id = ?", query.UserID) }
+		done := func() *gorm.DB {
+			return tasks().Where("status IN ? AND completed_at IS NOT NULL", query.DoneStatuses)
+		}
+		pending := func() *gorm.DB { return tasks().Where("status NOT IN ?", query.DoneStatuses) }
+
+		var rows []struct {
+			Status string
+			Count  int64
+		}
+		if err := tasks().Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			stats.ByStatus[row.Status] = row.Count
+		}
+
+		var averages struct {
+			Completion sql.NullFloat64
+			Cycle      sql.NullFloat64
+		}
+		err := done().Select("AVG(TIMESTAMPDIFF(SECOND, created_at, completed_at)) AS completion, " +
+			"AVG(TIMESTAMPDIFF(SECOND, started_at, completed_at)) AS cycle").Scan(&averages).Error
+		if err != nil {
+			return err
+		}
+		if averages.Completion.Valid {
+			stats.AvgCompletionSeconds = &averages.Completion.Float64
+		}
+		if averages.Cycle.Valid {
+			stats.AvgCycleSeconds = &averages.Cycle.Float64
+		}
+
+		if err := countByPeriod(tasks(), "created_at", query.Periods, stats.Created); err != nil {
+			return err
+		}
+		if err := countByPeriod(done(), "completed_at", query.Periods, stats.Completed); err != nil {
+			return err
+		}
+
+		if err := pending().Where("due_date IS NULL").Count(&stats.NoDueDate).Error; err != nil {
+			return err
+		}
+		return countByBucket(pending().Where("due_date IS NOT NULL"), "due_date", query.DueBounds, stats.Due)
+	})
+	return stats, err
+}
+
+// countByPeriod suma a counts las filas con column en cada periodo [periods[i], periods[i+1])
+func countByPeriod(query *gorm.DB, column string, periods []time.Time, counts []int64) error {
+	if len(periods) < 2 {
+		return nil
+	}
+	query = query.Where(column+" >= ? AND "+column+" < ?", periods[0], periods[len(periods)-1])
+	return countByBucket(query, column, periods[1:len(periods)-1], counts)
+}
+
+// countByBucket suma a counts las filas agrupadas por bucketExpr(column, bounds)
+func countByBucket(query *gorm.DB, column string, bounds []time.Time, counts []int64) error {
+	bucket, args := bucketExpr(column, bounds)
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	if err := query.Select(bucket+" AS bucket, COUNT(*) AS count", args...).Group("bucket").Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		counts[row.Bucket] += row.Count
+	}
+	return nil
+}
+
+// bucketExpr retorna una expresión SQL con el grupo de column: 0 antes de bounds[0],
+// i entre bounds[i-1] y bounds[i], y len(bounds) desde el último límite
+func bucketExpr(column string, bounds []time.Time) (string, []interface{}) {
+	if len(bounds) == 0 {
+		return "0", nil
+	}
+	var expr strings.Builder
+	args := make([]interface{}, len(bounds))
+	expr.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&expr, " WHEN %s < ? THEN %d", column, i)
+		args[i] = bound
+	}
+	fmt.Fprintf(&expr, " ELSE %d END", len(bounds))
+	return expr.String(), args
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserRepository define el acceso a los usuarios
+type UserRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	Create(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, userID uint, hash string) error
+	UpdateLanguage(ctx context.Context, userID uint, language string) error
+	UpdateTimezone(ctx context.Context, userID uint, timezone string) error
+	// Lock bloquea la fila del usuario hasta el final de la transacción (SELECT ... FOR UPDATE)
+	Lock(ctx context.Context, userID uint) error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, translateError(err)
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return translateError(writer(r.db, ctx).Create(user).Error)
+}
+
+// UpdatePassword actualiza solo el hash, sin pasar por los hooks del modelo
+func (r *gormUserRepository) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	err := writer(r.db, ctx).Model(&models.User{}).Where("id = ?", userID).UpdateColumn("password", hash).Error
+	return translateError(err)
+}
+
+// UpdateLanguage guarda el idioma preferido del usuario
+func (r *gormUserRepository) UpdateLanguage(ctx context.Context, userID uint, language string) error {
+	err := writer(r.db, ctx).Model(&models.User{}).Where("id = ?", userID).UpdateColumn("language", language).Error
+	return translateError(err)
+}
+
+// UpdateTimezone guarda la zona horaria del usuario
+func (r *gormUserRepository) UpdateTimezone(ctx context.Context, userID uint, timezone string) error {
+	err := writer(r.db, ctx).Model(&models.User{}).Where("id = ?", userID).UpdateColumn("timezone", timezone).Error
+	return translateError(err)
+}
+
+func (r *gormUserRepository) Lock(ctx context.Context, userID uint) error {
+	var user models.User
+	err := writer(r.db, ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error
+	return translateError(err)
+}
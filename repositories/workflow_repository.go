@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+
+	"go-task-manager-mvc/models"
+
+	"gorm.io/gorm"
+)
+
+// WorkflowRepository define el acceso al flujo de trabajo propio de cada usuario
+type WorkflowRepository interface {
+	// FindByUser retorna los estados en orden y las transiciones del usuario.
+	// Retorna ErrNotFound si el usuario no definió un flujo propio
+	FindByUser(ctx context.Context, userID uint) (*models.Workflow, error)
+	// Replace sustituye el flujo del usuario. Para que sea atómico debe ejecutarse en una transacción (Tx)
+	Replace(ctx context.Context, userID uint, workflow *models.Workflow) error
+	// Delete borra el flujo propio del usuario, que vuelve a usar el flujo por defecto
+	Delete(ctx context.Context, userID uint) error
+}
+
+type gormWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormWorkflowRepository) FindByUser(ctx context.Context, userID uint) (*models.Workflow, error) {
+	workflow := &models.Workflow{Custom: true}
+	db := r.db.WithContext(ctx)
+	if err := db.Where("user_id = ?", userID).Order("position").Find(&workflow.Statuses).Error; err != nil {
+		return nil, translateError(err)
+	}
+	if len(workflow.Statuses) == 0 {
+		return nil, ErrNotFound
+	}
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&workflow.Transitions).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return workflow, nil
+}
+
+func (r *gormWorkflowRepository) Replace(ctx context.Context, userID uint, workflow *models.Workflow) error {
+	if err := r.Delete(ctx, userID); err != nil {
+		return err
+	}
+	db := writer(r.db, ctx)
+	for i := range workflow.Statuses {
+		workflow.Statuses[i].ID = 0
+		workflow.Statuses[i].UserID = userID
+		workflow.Statuses[i].Position = i
+	}
+	if err := db.Create(&workflow.Statuses).Error; err != nil {
+		return translateError(err)
+	}
+	if len(workflow.Transitions) == 0 {
+		return nil
+	}
+	for i := range workflow.Transitions {
+		workflow.Transitions[i].ID = 0
+		workflow.Transitions[i].UserID = userID
+	}
+	return translateError(db.Create(&workflow.Transitions).Error)
+}
+
+func (r *gormWorkflowRepository) Delete(ctx context.Context, userID uint) error {
+	db := writer(r.db, ctx)
+	if err := db.Where("user_id = ?", userID).Delete(&models.WorkflowTransition{}).Error; err != nil {
+		return translateError(err)
+	}
+	return translateError(db.Where("user_id = ?", userID).Delete(&models.WorkflowStatus{}).Error)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/controllers"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/workers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes registra /healthz, /readyz y /version fuera de /api
+func SetupHealthRoutes(router *gin.Engine, backgroundWorkers *workers.Manager) {
+	health := controllers.NewHealthController(
+		controllers.ReadinessCheck{Name: "database", Check: config.PingDB},
+		controllers.ReadinessCheck{Name: "migrations", Check: checkMigrations},
+		controllers.ReadinessCheck{Name: "workers", Check: backgroundWorkers.Check},
+	)
+
+	router.GET("/healthz", health.Healthz)
+	router.GET("/readyz", health.Readyz)
+	router.GET("/version", controllers.Version)
+}
+
+// checkMigrations verifica que todas las migraciones del binario estén aplicadas
+func checkMigrations(ctx context.Context) error {
+	if config.DB == nil {
+		return errors.New("base de datos no conectada")
+	}
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		return err
+	}
+	return migrator.Check(ctx)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"go-task-manager-mvc/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMetricsRoutes expone las métricas de Prometheus en /metrics
+func SetupMetricsRoutes(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
@@ -1,26 +1,146 @@
 package routes
 
 import (
+	"time"
+
+	"go-task-manager-mvc/config"
 	"go-task-manager-mvc/controllers"
 	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/ratelimit"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine) {
+// Option ajusta el comportamiento de SetupRoutes
+type Option func(*options)
+
+type options struct {
+	rateLimitStore   ratelimit.Store
+	rateLimits       config.RateLimitConfig
+	idempotencyTTL   time.Duration
+	idempotencyLease time.Duration
+}
+
+// WithRateLimits aplica las cuotas de cfg guardando los buckets en store
+func WithRateLimits(store ratelimit.Store, cfg config.RateLimitConfig) Option {
+	return func(o *options) {
+		o.rateLimitStore = store
+		o.rateLimits = cfg
+	}
+}
+
+// WithIdempotencyTTL cambia cuánto tiempo se guardan las respuestas de cada Idempotency-Key
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.idempotencyTTL = ttl
+	}
+}
+
+// WithIdempotencyLease cambia cuánto puede bloquear una Idempotency-Key una petición en curso
+func WithIdempotencyLease(lease time.Duration) Option {
+	return func(o *options) {
+		o.idempotencyLease = lease
+	}
+}
+
+// rateLimit retorna el límite del grupo, o un middleware que no limita si no hay cuotas configuradas
+func (o options) rateLimit(group string, requests int, period config.Duration, key middleware.RateLimitKey) gin.HandlerFunc {
+	if o.rateLimitStore == nil || !o.rateLimits.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limit := ratelimit.Limit{Requests: requests, Period: period.Std()}
+	return middleware.RateLimit(o.rateLimitStore, group, limit, key)
+}
+
+// SetupRoutes construye servicios y controladores sobre los repositorios dados y registra las rutas
+func SetupRoutes(router *gin.Engine, repos repositories.Repositories, opts ...Option) {
+	o := options{
+		idempotencyTTL:   config.Default().Idempotency.TTL.Std(),
+		idempotencyLease: config.Default().Idempotency.Lease.Std(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	limits := o.rateLimits
+
+	authService := services.NewAuthService(repos)
+	sessionService := services.NewSessionService(repos.Sessions)
+	apiKeyService := services.NewAPIKeyService(repos.APIKeys)
+	idempotencyService := services.NewIdempotencyService(repos.Idempotency, o.idempotencyTTL, o.idempotencyLease)
+
+	users := controllers.NewUserController(authService, services.NewUserService(repos.Users))
+	oidc := controllers.NewOIDCController(authService, config.OIDC)
+	tasks := controllers.NewTaskController(services.NewTaskService(repos))
+	workflows := controllers.NewWorkflowController(services.NewWorkflowService(repos))
+	stats := controllers.NewStatsController(services.NewStatsService(repos))
+	apiKeys := controllers.NewAPIKeyController(apiKeyService)
+	sessions := controllers.NewSessionController(sessionService)
+
+	router.NoRoute(controllers.NotFound)
+
+	// Claves públicas para que otros servicios verifiquen nuestros tokens
+	router.GET("/.well-known/jwks.json", controllers.JWKS)
+
 	api := router.Group("/api")
+	api.Use(middleware.ReadYourWrites())
 
-	// 🔐 Rutas públicas
-	api.POST("/register", controllers.RegisterUser)
-	api.POST("/login", controllers.LoginUser)
+	// 🔐 Rutas públicas, con una cuota estricta por IP contra el abuso de registro y login
+	authLimit := o.rateLimit("auth", limits.AuthRequests, limits.AuthPeriod, middleware.ByClientIP)
+	api.POST("/register", authLimit, users.RegisterUser)
+	api.POST("/login", authLimit, users.LoginUser)
+	api.GET("/auth/oidc/login", authLimit, oidc.OIDCLogin)
+	api.GET("/auth/oidc/callback", authLimit, oidc.OIDCCallback)
 
-	// 🔒 Rutas protegidas con JWT
+	// 🔒 Rutas protegidas con JWT o API key, con cuota por usuario
 	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(
+		middleware.AuthMiddleware(sessionService, apiKeyService),
+		o.rateLimit("api", limits.APIRequests, limits.APIPeriod, middleware.ByUser),
+	)
 	{
-		protected.GET("/tasks", controllers.GetTasks)
-		protected.POST("/tasks", controllers.CreateTask)
-		protected.PUT("/tasks/:id", controllers.UpdateTask)
-		protected.DELETE("/tasks/:id", controllers.DeleteTask)
+		read := middleware.RequireScope(models.ScopeTasksRead)
+		write := middleware.RequireScope(models.ScopeTasksWrite)
+		// Los clientes pueden reintentar los cambios con Idempotency-Key sin duplicarlos
+		idempotent := middleware.Idempotency(idempotencyService)
+
+		protected.GET("/tasks", read, tasks.GetTasks)
+		protected.GET("/task-statuses", read, tasks.GetStatuses)
+		protected.POST("/tasks", write, idempotent, tasks.CreateTask)
+		protected.PUT("/tasks/:id", write, idempotent, tasks.UpdateTask)
+		protected.DELETE("/tasks/:id", write, idempotent, tasks.DeleteTask)
+		protected.GET("/tasks/:id/transitions", read, tasks.GetTransitions)
+
+		// 🗃️ Tablero: columnas por estado y movimientos con límite de tareas por columna
+		protected.GET("/board", read, tasks.GetBoard)
+		protected.POST("/tasks/:id/move", write, idempotent, tasks.MoveTask)
+
+		// 📊 Estadísticas de productividad en la zona horaria del usuario
+		protected.GET("/stats", read, stats.GetStats)
+
+		// 🔑 Gestión de API keys (solo con sesión JWT)
+		manageKeys := middleware.RequireScope(models.ScopeAPIKeysManage)
+		protected.GET("/api-keys", manageKeys, apiKeys.GetAPIKeys)
+		protected.POST("/api-keys", manageKeys, apiKeys.CreateAPIKey)
+		protected.DELETE("/api-keys/:id", manageKeys, apiKeys.RevokeAPIKey)
+
+		// 💻 Sesiones activas (solo con sesión JWT)
+		manageSessions := middleware.RequireScope(models.ScopeSessionsManage)
+		protected.GET("/me/sessions", manageSessions, sessions.GetSessions)
+		protected.DELETE("/me/sessions", manageSessions, sessions.RevokeAllSessions)
+		protected.DELETE("/me/sessions/:id", manageSessions, sessions.RevokeSession)
+
+		// 🌐 Preferencias del usuario (solo con sesión JWT)
+		manageProfile := middleware.RequireScope(models.ScopeProfileManage)
+		protected.GET("/me/preferences", manageProfile, users.GetPreferences)
+		protected.PUT("/me/preferences", manageProfile, users.UpdatePreferences)
+
+		// 🗂️ Flujo de trabajo de las tareas: estados propios y transiciones permitidas (solo con sesión JWT)
+		manageWorkflow := middleware.RequireScope(models.ScopeWorkflowManage)
+		protected.GET("/me/workflow", manageWorkflow, workflows.GetWorkflow)
+		protected.PUT("/me/workflow", manageWorkflow, workflows.UpdateWorkflow)
+		protected.DELETE("/me/workflow", manageWorkflow, workflows.ResetWorkflow)
 	}
 }
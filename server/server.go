@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"go-task-manager-mvc/config"
+)
+
+// Plazo de las funciones de limpieza, aparte del de drenar las peticiones: si las peticiones
+// agotan el timeout de apagado, los workers y la base de datos se cierran igualmente
+const cleanupTimeout = 10 * time.Second
+
+// Server envuelve http.Server con timeouts configurables y apagado ordenado
+type Server struct {
+	http            *http.Server
+	shutdownTimeout time.Duration
+	onShutdown      []func(ctx context.Context) error
+}
+
+// New crea el servidor HTTP con los timeouts de la configuración
+func New(cfg config.ServerConfig, handler http.Handler) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:              ":" + cfg.Port,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout.Std(),
+			ReadHeaderTimeout: cfg.ReadTimeout.Std(),
+			WriteTimeout:      cfg.WriteTimeout.Std(),
+			IdleTimeout:       cfg.IdleTimeout.Std(),
+		},
+		shutdownTimeout: cfg.ShutdownTimeout.Std(),
+	}
+}
+
+// OnShutdown registra una función de limpieza que se ejecuta, en orden de registro,
+// después de drenar las peticiones en curso (detener workers, cerrar la base de datos...)
+func (s *Server) OnShutdown(fn func(ctx context.Context) error) {
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+// ListenAndServe escucha en el puerto configurado y atiende peticiones hasta que ctx se cancela
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve atiende peticiones en ln hasta que ctx se cancela. Entonces deja de aceptar
+// conexiones, espera a las peticiones en curso hasta el timeout de apagado y ejecuta
+// las funciones de limpieza con su propio plazo
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// El servidor terminó por sí solo: no hay nada que drenar
+		return errors.Join(err, s.cleanup())
+	case <-ctx.Done():
+	}
+
+	slog.Info("Apagando el servidor", "shutdown_timeout", s.shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := s.http.Shutdown(shutdownCtx)
+	if err != nil {
+		// Se agotó el plazo: cortar las conexiones que sigan abiertas
+		s.http.Close()
+	}
+	return errors.Join(err, s.cleanup())
+}
+
+// cleanup ejecuta todas las funciones de limpieza aunque alguna falle, como mucho durante cleanupTimeout
+func (s *Server) cleanup() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, fn := range s.onShutdown {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("API key no encontrada")
+	ErrAPIKeyInvalid        = errors.New("API key inválida")
+	ErrAPIKeyInactive       = errors.New("API key inválida, revocada o expirada")
+	ErrAPIKeyExpiresInPast  = errors.New("la fecha de expiración no puede ser en el pasado")
+	errAPIKeyGenerateFailed = errors.New("error al generar la API key")
+)
+
+// Intentos de generar una API key con un prefijo libre
+const apiKeyGenerateAttempts = 3
+
+// APIKeyService gestiona las API keys de los usuarios
+type APIKeyService struct {
+	apiKeys repositories.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeys repositories.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{apiKeys: apiKeys}
+}
+
+// Create genera y guarda una API key. Retorna la clave completa, que no se vuelve a poder obtener
+func (s *APIKeyService) Create(ctx context.Context, userID uint, request models.APIKeyCreateRequest) (string, *models.APIKey, error) {
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		return "", nil, ErrAPIKeyExpiresInPast
+	}
+
+	// El prefijo es aleatorio y único: si coincide con el de otra clave se genera otra
+	for attempt := 1; ; attempt++ {
+		key, prefix, hash, err := config.GenerateAPIKey()
+		if err != nil {
+			return "", nil, errAPIKeyGenerateFailed
+		}
+
+		apiKey := request.ToAPIKey(userID)
+		apiKey.Prefix = prefix
+		apiKey.KeyHash = hash
+
+		err = s.apiKeys.Create(ctx, &apiKey)
+		if errors.Is(err, repositories.ErrDuplicate) && attempt < apiKeyGenerateAttempts {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return key, &apiKey, nil
+	}
+}
+
+// List retorna las API keys del usuario
+func (s *APIKeyService) List(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	return s.apiKeys.FindByUser(ctx, userID)
+}
+
+// Revoke revoca una API key del usuario. Revocar una clave ya revocada no es un error
+func (s *APIKeyService) Revoke(ctx context.Context, userID uint, apiKeyID uint) (*models.APIKey, error) {
+	apiKey, err := s.apiKeys.FindByIDForUser(ctx, apiKeyID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiKey.IsRevoked() {
+		now := time.Now()
+		if err := s.apiKeys.Revoke(ctx, apiKey.ID, now); err != nil {
+			return nil, err
+		}
+		apiKey.RevokedAt = &now
+	}
+	return apiKey, nil
+}
+
+// Authenticate valida una API key y retorna la clave con su usuario
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	prefix, err := config.ParseAPIKeyPrefix(key)
+	if err != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	apiKey, err := s.apiKeys.FindByPrefix(ctx, prefix)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.CompareAPIKey(key, apiKey.KeyHash) || !apiKey.IsActive() {
+		return nil, ErrAPIKeyInactive
+	}
+
+	now := time.Now()
+	if err := s.apiKeys.TouchLastUsed(ctx, apiKey.ID, now); err == nil {
+		apiKey.LastUsedAt = &now
+	}
+	return apiKey, nil
+}
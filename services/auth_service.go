@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/metrics"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+var (
+	ErrUserNotFound    = errors.New("usuario no encontrado")
+	ErrWrongPassword   = errors.New("contraseña incorrecta")
+	ErrPasswordHash    = errors.New("error al encriptar la contraseña")
+	ErrSignupDisabled  = errors.New("registro automático deshabilitado")
+	ErrUsernameTaken   = errors.New("el nombre de usuario ya está en uso")
+	ErrEmailTaken      = errors.New("el email ya está registrado")
+	errSessionCreation = errors.New("no se pudo crear la sesión")
+)
+
+// PasswordPolicyError indica que la contraseña no cumple la política configurada
+type PasswordPolicyError struct {
+	Violations []i18n.Message
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+	return "la contraseña no cumple la política de seguridad: " + strings.Join(messages, "; ")
+}
+
+// RegisterInput son los datos para registrar un usuario con contraseña
+type RegisterInput struct {
+	Username string
+	Email    string
+	Password string
+}
+
+// ExternalIdentity es la identidad verificada por un proveedor OIDC
+type ExternalIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// AuthService contiene el registro, el login y la emisión de sesiones
+type AuthService struct {
+	repos repositories.Repositories
+}
+
+func NewAuthService(repos repositories.Repositories) *AuthService {
+	return &AuthService{repos: repos}
+}
+
+// Register valida la contraseña contra la política y crea el usuario
+func (s *AuthService) Register(ctx context.Context, input RegisterInput) (*models.User, error) {
+	if violations := config.PasswordRules.Validate(input.Password, input.Username, input.Email); len(violations) > 0 {
+		return nil, &PasswordPolicyError{Violations: violations}
+	}
+
+	hashedPassword, err := config.HashPassword(input.Password)
+	if err != nil {
+		return nil, ErrPasswordHash
+	}
+
+	user := models.User{
+		Username: input.Username,
+		Email:    input.Email,
+		Password: hashedPassword,
+	}
+	err = s.repos.Users.Create(ctx, &user)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, s.duplicateUserError(ctx, input.Email)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// duplicateUserError indica qué dato del registro ya está en uso: el índice único
+// que falló no se distingue en el error de MySQL sin depender del nombre del índice
+func (s *AuthService) duplicateUserError(ctx context.Context, email string) error {
+	if _, err := s.repos.Users.FindByEmail(ctx, email); err == nil {
+		return ErrEmailTaken
+	}
+	return ErrUsernameTaken
+}
+
+// Login verifica las credenciales y abre una sesión. Retorna el token de la sesión
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (string, *models.User, error) {
+	user, err := s.repos.Users.FindByEmail(ctx, email)
+	if errors.Is(err, repositories.ErrNotFound) {
+		metrics.RecordLogin("password", false)
+		return "", nil, ErrUserNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	ok, needsRehash := config.VerifyPassword(password, user.Password)
+	if !ok {
+		metrics.RecordLogin("password", false)
+		return "", nil, ErrWrongPassword
+	}
+
+	// Actualizar hashes con algoritmo o coste antiguos ahora que conocemos la contraseña
+	if needsRehash {
+		if hashedPassword, err := config.HashPassword(password); err == nil {
+			if err := s.repos.Users.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+				logging.FromContext(ctx).Warn("No se pudo actualizar el hash de la contraseña", "user_id", user.ID, "error", err)
+			} else {
+				user.Password = hashedPassword
+			}
+		}
+	}
+
+	token, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return "", nil, err
+	}
+	metrics.RecordLogin("password", true)
+	return token, user, nil
+}
+
+// LoginWithIdentity abre una sesión para el usuario vinculado a una identidad externa.
+// Si no hay vínculo lo crea por email verificado o, si allowSignup, crea un usuario nuevo.
+// Retorna ErrEmailTaken si el email sin verificar es el de otro usuario
+func (s *AuthService) LoginWithIdentity(ctx context.Context, identity ExternalIdentity, allowSignup bool, client ClientInfo) (string, *models.User, error) {
+	var user *models.User
+
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		linked, err := repos.Identities.FindByIssuerAndSubject(ctx, identity.Issuer, identity.Subject)
+		if err == nil {
+			user = &linked.User
+			return nil
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+
+		// Vincular con un usuario existente solo si el proveedor verificó el email.
+		// Si no lo verificó, el email no demuestra que la cuenta sea suya
+		if identity.Email != "" {
+			existing, err := repos.Users.FindByEmail(ctx, identity.Email)
+			switch {
+			case err == nil && identity.EmailVerified:
+				user = existing
+			case err == nil:
+				return ErrEmailTaken
+			case !errors.Is(err, repositories.ErrNotFound):
+				return err
+			}
+		}
+
+		if user == nil {
+			if !allowSignup || identity.Email == "" {
+				return ErrSignupDisabled
+			}
+
+			username, err := availableUsername(ctx, repos.Users, identity)
+			if err != nil {
+				return err
+			}
+
+			// Sin contraseña local: solo puede iniciar sesión a través del proveedor
+			user = &models.User{Username: username, Email: identity.Email}
+			err = repos.Users.Create(ctx, user)
+			if errors.Is(err, repositories.ErrDuplicate) {
+				// Otro registro usó el email o el nombre a la vez
+				return s.duplicateUserError(ctx, identity.Email)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return repos.Identities.Create(ctx, &models.UserIdentity{
+			UserID:  user.ID,
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+			Email:   identity.Email,
+		})
+	})
+	if errors.Is(err, ErrSignupDisabled) {
+		metrics.RecordLogin("oidc", false)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return "", nil, err
+	}
+	metrics.RecordLogin("oidc", true)
+	return token, user, nil
+}
+
+// startSession registra una sesión para el usuario y emite un token asociado a ella
+func (s *AuthService) startSession(ctx context.Context, user *models.User, client ClientInfo) (string, error) {
+	now := time.Now()
+	session := models.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 512),
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(config.TokenTTL),
+	}
+
+	if err := s.repos.Sessions.Create(ctx, &session); err != nil {
+		return "", fmt.Errorf("%w: %w", errSessionCreation, err)
+	}
+
+	return config.GenerateToken(user.Username, session.ID)
+}
+
+var invalidUsernameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// availableUsername deriva un nombre de usuario libre a partir de la identidad
+func availableUsername(ctx context.Context, users repositories.UserRepository, identity ExternalIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base = strings.Split(identity.Email, "@")[0]
+	}
+	base = invalidUsernameChars.ReplaceAllString(base, "_")
+	if len(base) < 3 {
+		base = "user_" + base
+	}
+
+	candidate := base
+	for i := 2; i <= 100; i++ {
+		exists, err := users.UsernameExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	return "", errors.New("no se encontró un nombre de usuario disponible")
+}
+
+// truncate recorta un texto a un máximo de bytes
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
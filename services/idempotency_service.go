@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+var (
+	ErrIdempotencyInProgress = errors.New("hay una petición en curso con esta Idempotency-Key")
+	ErrIdempotencyKeyReused  = errors.New("la Idempotency-Key ya se usó con otra petición")
+)
+
+// IdempotencyService guarda la primera respuesta de cada Idempotency-Key durante ttl.
+// Una petición en curso bloquea la clave como mucho durante lease
+type IdempotencyService struct {
+	keys  repositories.IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(keys repositories.IdempotencyRepository, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{keys: keys, ttl: ttl, lease: lease}
+}
+
+// Begin reserva la clave del usuario para la petición identificada por requestHash.
+// Si la petición ya se atendió retorna la respuesta guardada con started=false.
+// Si la original sigue en curso pasado el plazo, se da por abandonada y se reutiliza la clave
+func (s *IdempotencyService) Begin(ctx context.Context, userID uint, key string, requestHash string) (record *models.IdempotencyKey, started bool, err error) {
+	now := time.Now()
+	lockedUntil := now.Add(s.lease)
+	record = &models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(s.ttl),
+		LockedUntil: &lockedUntil,
+	}
+
+	err = s.keys.Create(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+	if !errors.Is(err, repositories.ErrDuplicate) {
+		return nil, false, err
+	}
+
+	replaced, err := s.keys.ReplaceExpired(ctx, record, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if replaced {
+		return record, true, nil
+	}
+
+	existing, err := s.keys.FindByKey(ctx, userID, key)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// La petición original falló y liberó la clave mientras tanto
+		return nil, false, ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, false, ErrIdempotencyInProgress
+	}
+	return existing, false, nil
+}
+
+// Complete guarda la respuesta para repetirla en los reintentos
+func (s *IdempotencyService) Complete(ctx context.Context, record *models.IdempotencyKey, status int, contentType string, body []byte) error {
+	return s.keys.Complete(ctx, record.ID, status, contentType, body)
+}
+
+// Release libera la clave para que el cliente pueda reintentar
+func (s *IdempotencyService) Release(ctx context.Context, record *models.IdempotencyKey) error {
+	return s.keys.Delete(ctx, record.ID)
+}
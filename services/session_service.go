@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// Frecuencia máxima con la que se actualiza last_seen_at de una sesión
+const sessionTouchInterval = time.Minute
+
+var (
+	ErrSessionNotFound = errors.New("sesión no encontrada")
+	ErrSessionInactive = errors.New("sesión cerrada o expirada")
+)
+
+// ClientInfo describe el cliente que inicia una sesión
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// SessionService gestiona las sesiones de login de los usuarios
+type SessionService struct {
+	sessions repositories.SessionRepository
+}
+
+func NewSessionService(sessions repositories.SessionRepository) *SessionService {
+	return &SessionService{sessions: sessions}
+}
+
+// Authenticate busca la sesión de un token y verifica que siga activa y sea del mismo usuario
+func (s *SessionService) Authenticate(ctx context.Context, claims *config.Claims) (*models.Session, error) {
+	if claims.SessionID == 0 {
+		return nil, ErrSessionInactive
+	}
+
+	session, err := s.sessions.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrSessionInactive
+		}
+		return nil, err
+	}
+	if !session.IsActive() || session.User.Username != claims.Username {
+		return nil, ErrSessionInactive
+	}
+
+	// Actualizar la última actividad como mucho una vez por minuto
+	if now := time.Now(); now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessions.Touch(ctx, session.ID, now); err == nil {
+			session.LastSeenAt = now
+		}
+	}
+	return session, nil
+}
+
+// List retorna las sesiones activas del usuario
+func (s *SessionService) List(ctx context.Context, userID uint) ([]models.Session, error) {
+	return s.sessions.FindActiveByUser(ctx, userID, time.Now())
+}
+
+// Revoke cierra una sesión del usuario. Cerrar una sesión ya cerrada no es un error
+func (s *SessionService) Revoke(ctx context.Context, userID uint, sessionID uint) (*models.Session, error) {
+	session, err := s.sessions.FindByIDForUser(ctx, sessionID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.RevokedAt == nil {
+		now := time.Now()
+		if err := s.sessions.Revoke(ctx, session.ID, now); err != nil {
+			return nil, err
+		}
+		session.RevokedAt = &now
+	}
+	return session, nil
+}
+
+// RevokeAll cierra todas las sesiones del usuario salvo exceptID (0 para cerrarlas todas)
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint, exceptID uint) (int64, error) {
+	return s.sessions.RevokeAllByUser(ctx, userID, exceptID, time.Now())
+}
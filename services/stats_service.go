@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// StatsService calcula las estadísticas de productividad de cada usuario
+type StatsService struct {
+	repos repositories.Repositories
+}
+
+func NewStatsService(repos repositories.Repositories) *StatsService {
+	return &StatsService{repos: repos}
+}
+
+// Stats resume las tareas del usuario: por estado, completadas, vencidas, series de
+// creadas y completadas en el rango pedido y próximas fechas límite.
+// Los estados completados son los del flujo del usuario. Sin zona horaria en la petición
+// se usa la de sus preferencias
+func (s *StatsService) Stats(ctx context.Context, userID uint, request models.StatsRequest) (*models.TaskStats, error) {
+	if request.Timezone == "" {
+		user, err := s.repos.Users.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		request.Timezone = user.Timezone
+	}
+
+	statsRange, err := request.Resolve(time.Now())
+	if err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+
+	workflow, err := loadWorkflow(ctx, s.repos.Workflows, userID)
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	for _, status := range workflow.Statuses {
+		if status.Done {
+			done = append(done, status.Key)
+		}
+	}
+
+	counts, err := s.repos.Tasks.Stats(ctx, repositories.TaskStatsQuery{
+		UserID:       userID,
+		DoneStatuses: done,
+		Periods:      statsRange.Periods,
+		DueBounds:    statsRange.DueBounds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.TaskStats{
+		Workflow:          workflow,
+		Range:             statsRange,
+		CreatedByPeriod:   counts.Created,
+		CompletedByPeriod: counts.Completed,
+		Due:               counts.Due,
+		NoDueDate:         counts.NoDueDate,
+	}
+	stats.AverageCompletion = seconds(counts.AvgCompletionSeconds)
+	stats.AverageCycle = seconds(counts.AvgCycleSeconds)
+
+	// Primero los estados del flujo, aunque no tengan tareas, y después los que ya no incluye
+	for _, status := range workflow.Statuses {
+		stats.ByStatus = append(stats.ByStatus, models.StatusCount{Status: status.Key, Count: counts.ByStatus[status.Key]})
+	}
+	var others []string
+	for status := range counts.ByStatus {
+		if !workflow.Has(status) {
+			others = append(others, status)
+		}
+	}
+	sort.Strings(others)
+	for _, status := range others {
+		stats.ByStatus = append(stats.ByStatus, models.StatusCount{Status: status, Count: counts.ByStatus[status]})
+	}
+
+	for _, status := range stats.ByStatus {
+		stats.Total += status.Count
+		if workflow.IsDone(status.Status) {
+			stats.Completed += status.Count
+		}
+	}
+	return stats, nil
+}
+
+// seconds convierte una media en segundos de la base de datos a una duración; nil si no hay media
+func seconds(average *float64) *time.Duration {
+	if average == nil {
+		return nil
+	}
+	d := time.Duration(*average * float64(time.Second)).Round(time.Second)
+	return &d
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+var (
+	ErrTaskNotFound  = errors.New("tarea no encontrada")
+	ErrInvalidStatus = errors.New("estado inválido")
+)
+
+// ValidationError envuelve un error de validación de datos enviados por el cliente
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// WIPLimitError indica que la columna de destino ya tiene el máximo de tareas en curso
+type WIPLimitError struct {
+	Status string
+	Limit  int
+}
+
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("la columna %s ya tiene %d tareas", e.Status, e.Limit)
+}
+
+// TaskService contiene las reglas de negocio de las tareas
+type TaskService struct {
+	repos repositories.Repositories
+}
+
+func NewTaskService(repos repositories.Repositories) *TaskService {
+	return &TaskService{repos: repos}
+}
+
+// Workflow retorna el flujo de trabajo con el que se validan las tareas del usuario
+func (s *TaskService) Workflow(ctx context.Context, userID uint) (*models.Workflow, error) {
+	return loadWorkflow(ctx, s.repos.Workflows, userID)
+}
+
+// List retorna las tareas del usuario, opcionalmente filtradas por un estado de su flujo
+// (se aceptan los alias en español)
+func (s *TaskService) List(ctx context.Context, userID uint, status string) ([]models.Task, error) {
+	workflow, err := s.Workflow(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status = models.NormalizeTaskStatus(status)
+	if status != "" && !workflow.Has(status) {
+		return nil, ErrInvalidStatus
+	}
+
+	tasks, err := s.repos.Tasks.FindByUser(ctx, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].UseWorkflow(workflow)
+	}
+	return tasks, nil
+}
+
+// Board retorna las tareas del usuario agrupadas en una columna por cada estado de su flujo,
+// ordenadas por posición
+func (s *TaskService) Board(ctx context.Context, userID uint) (*models.Board, error) {
+	workflow, err := s.Workflow(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repos.Tasks.FindByUser(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].UseWorkflow(workflow)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return models.NewBoard(workflow, tasks), nil
+}
+
+// Create valida y guarda una nueva tarea del usuario al final de su columna
+func (s *TaskService) Create(ctx context.Context, userID uint, request models.TaskCreateRequest) (*models.Task, error) {
+	task := request.ToTask(userID)
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		workflow, err := loadWorkflow(ctx, repos.Workflows, userID)
+		if err != nil {
+			return err
+		}
+		task.UseWorkflow(workflow)
+		if err := task.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+
+		column, err := enterColumn(ctx, repos, &task)
+		if err != nil {
+			return err
+		}
+		task.Position = endOfColumn(column)
+		transition := task.RecordStatusChange(time.Now())
+		if err := repos.Tasks.Create(ctx, &task); err != nil {
+			return err
+		}
+		return recordTransition(ctx, repos, &task, transition)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Update aplica los cambios a una tarea del usuario. Si cambia de estado pasa al final de la nueva columna
+func (s *TaskService) Update(ctx context.Context, userID uint, taskID uint, request models.TaskUpdateRequest) (*models.Task, error) {
+	var task *models.Task
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		var err error
+		if task, err = findTask(ctx, repos, userID, taskID); err != nil {
+			return err
+		}
+
+		previous := task.Status
+		request.ApplyToTask(task)
+		if err := task.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+
+		if task.Status != previous {
+			column, err := enterColumn(ctx, repos, task)
+			if err != nil {
+				return err
+			}
+			task.Position = endOfColumn(column)
+			if err := compactColumn(ctx, repos, userID, previous, task.ID); err != nil {
+				return err
+			}
+		}
+		return saveTask(ctx, repos, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Move cambia la tarea de estado y de posición a la vez. Las posiciones de la columna de destino
+// se renumeran para dejar la tarea en la posición pedida (al final si no se indica)
+// y las de la columna de origen para cerrar el hueco
+func (s *TaskService) Move(ctx context.Context, userID uint, taskID uint, request models.TaskMoveRequest) (*models.Task, error) {
+	var task *models.Task
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		var err error
+		if task, err = findTask(ctx, repos, userID, taskID); err != nil {
+			return err
+		}
+
+		previous := task.Status
+		if request.Status != "" {
+			task.Status = string(request.Status)
+		}
+		if err := task.Validate(); err != nil {
+			return &ValidationError{Err: err}
+		}
+
+		var column []models.Task
+		if task.Status != previous {
+			if column, err = enterColumn(ctx, repos, task); err != nil {
+				return err
+			}
+			if err := compactColumn(ctx, repos, userID, previous, task.ID); err != nil {
+				return err
+			}
+		} else if column, err = lockedColumn(ctx, repos, userID, task.Status); err != nil {
+			return err
+		}
+
+		column = removeTask(column, task.ID)
+		position := len(column)
+		if request.Position != nil && *request.Position < position {
+			position = *request.Position
+		}
+		task.Position = position
+
+		for i, other := range column {
+			newPosition := i
+			if i >= position {
+				newPosition = i + 1
+			}
+			if other.Position != newPosition {
+				if err := repos.Tasks.UpdatePosition(ctx, other.ID, newPosition); err != nil {
+					return err
+				}
+			}
+		}
+		return saveTask(ctx, repos, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Transitions retorna una tarea del usuario con sus cambios de estado, del más antiguo al más reciente
+func (s *TaskService) Transitions(ctx context.Context, userID uint, taskID uint) (*models.Task, []models.TaskStatusTransition, error) {
+	task, err := findTask(ctx, s.repos, userID, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transitions, err := s.repos.Tasks.FindTransitions(ctx, task.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, transitions, nil
+}
+
+// Delete elimina una tarea del usuario y la retorna. Las posiciones de su columna se renumeran
+// para cerrar el hueco
+func (s *TaskService) Delete(ctx context.Context, userID uint, taskID uint) (*models.Task, error) {
+	var task *models.Task
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		var err error
+		if task, err = findTask(ctx, repos, userID, taskID); err != nil {
+			return err
+		}
+		if err := repos.Users.Lock(ctx, userID); err != nil {
+			return err
+		}
+		if err := repos.Tasks.Delete(ctx, task); err != nil {
+			return err
+		}
+		return compactColumn(ctx, repos, userID, task.Status, task.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// findTask busca una tarea verificando que pertenezca al usuario y la asocia a su flujo de trabajo
+func findTask(ctx context.Context, repos repositories.Repositories, userID uint, taskID uint) (*models.Task, error) {
+	task, err := repos.Tasks.FindByIDForUser(ctx, taskID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := loadWorkflow(ctx, repos.Workflows, userID)
+	if err != nil {
+		return nil, err
+	}
+	task.UseWorkflow(workflow)
+	return task, nil
+}
+
+// saveTask guarda los cambios de una tarea existente y, si cambió de estado, actualiza
+// StartedAt y CompletedAt y registra la transición
+func saveTask(ctx context.Context, repos repositories.Repositories, task *models.Task) error {
+	transition := task.RecordStatusChange(time.Now())
+	if err := repos.Tasks.Update(ctx, task); err != nil {
+		return err
+	}
+	return recordTransition(ctx, repos, task, transition)
+}
+
+// recordTransition guarda la transición de la tarea ya guardada. transition es nil si el estado no cambió
+func recordTransition(ctx context.Context, repos repositories.Repositories, task *models.Task, transition *models.TaskStatusTransition) error {
+	if transition == nil {
+		return nil
+	}
+	transition.TaskID = task.ID
+	return repos.Tasks.RecordTransition(ctx, transition)
+}
+
+// lockedColumn retorna las tareas de una columna del usuario después de bloquear su fila en users.
+// FindColumn solo bloquea las tareas que ya están en la columna: con la columna vacía dos
+// transacciones podrían añadir tareas a la vez y superar el límite. Todas las que cambian columnas
+// del usuario pasan por este bloqueo, así que se ejecutan una detrás de otra
+func lockedColumn(ctx context.Context, repos repositories.Repositories, userID uint, status string) ([]models.Task, error) {
+	if err := repos.Users.Lock(ctx, userID); err != nil {
+		return nil, err
+	}
+	return repos.Tasks.FindColumn(ctx, userID, status)
+}
+
+// enterColumn bloquea la columna a la que entra la tarea y retorna sus tareas.
+// Retorna WIPLimitError si la columna ya tiene el máximo de tareas que permite su estado
+func enterColumn(ctx context.Context, repos repositories.Repositories, task *models.Task) ([]models.Task, error) {
+	column, err := lockedColumn(ctx, repos, task.UserID, task.Status)
+	if err != nil {
+		return nil, err
+	}
+	column = removeTask(column, task.ID)
+	if limit := task.Workflow().WIPLimit(task.Status); limit > 0 && len(column) >= limit {
+		return nil, &WIPLimitError{Status: task.Status, Limit: limit}
+	}
+	return column, nil
+}
+
+// compactColumn renumera desde 0 las tareas de la columna que deja la tarea taskID
+func compactColumn(ctx context.Context, repos repositories.Repositories, userID uint, status string, taskID uint) error {
+	column, err := repos.Tasks.FindColumn(ctx, userID, status)
+	if err != nil {
+		return err
+	}
+	for i, other := range removeTask(column, taskID) {
+		if other.Position != i {
+			if err := repos.Tasks.UpdatePosition(ctx, other.ID, i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// endOfColumn retorna la posición siguiente a la última tarea de la columna
+func endOfColumn(column []models.Task) int {
+	end := 0
+	for _, task := range column {
+		if task.Position >= end {
+			end = task.Position + 1
+		}
+	}
+	return end
+}
+
+// removeTask quita de la columna la tarea con el ID indicado
+func removeTask(column []models.Task, taskID uint) []models.Task {
+	result := column[:0:0]
+	for _, task := range column {
+		if task.ID != taskID {
+			result = append(result, task)
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// UserService gestiona el perfil y las preferencias del usuario
+type UserService struct {
+	users repositories.UserRepository
+}
+
+func NewUserService(users repositories.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+// Get retorna el usuario autenticado
+func (s *UserService) Get(ctx context.Context, userID uint) (*models.User, error) {
+	return s.users.FindByID(ctx, userID)
+}
+
+// SetPreferences guarda las preferencias indicadas en la petición. El idioma se guarda
+// como código soportado ("en-US" se guarda como "en"); los valores vacíos borran la preferencia
+func (s *UserService) SetPreferences(ctx context.Context, userID uint, request models.PreferencesRequest) (*models.User, error) {
+	if err := request.Validate(); err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+
+	if request.Language != nil {
+		language := *request.Language
+		if locale, ok := i18n.Parse(language); ok {
+			language = string(locale)
+		}
+		if err := s.users.UpdateLanguage(ctx, userID, language); err != nil {
+			return nil, err
+		}
+	}
+	if request.Timezone != nil {
+		if err := s.users.UpdateTimezone(ctx, userID, *request.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	return s.users.FindByID(ctx, userID)
+}
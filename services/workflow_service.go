@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// StatusInUseError indica que hay tareas en estados que el nuevo flujo no incluye
+type StatusInUseError struct {
+	Statuses []string
+}
+
+func (e *StatusInUseError) Error() string {
+	return "hay tareas en estados que el flujo no incluye: " + strings.Join(e.Statuses, ", ")
+}
+
+// WorkflowService gestiona el flujo de trabajo de las tareas de cada usuario.
+// El flujo es por usuario y no por espacio de trabajo: la API no tiene equipos ni tareas compartidas
+type WorkflowService struct {
+	repos repositories.Repositories
+}
+
+func NewWorkflowService(repos repositories.Repositories) *WorkflowService {
+	return &WorkflowService{repos: repos}
+}
+
+// Get retorna el flujo del usuario, o el flujo por defecto si no definió uno
+func (s *WorkflowService) Get(ctx context.Context, userID uint) (*models.Workflow, error) {
+	return loadWorkflow(ctx, s.repos.Workflows, userID)
+}
+
+// Replace valida y guarda el flujo del usuario. Retorna StatusInUseError si alguna
+// de sus tareas está en un estado que el nuevo flujo no incluye.
+// El inicio y la finalización de las tareas se ajustan al estado inicial y a los estados done del flujo
+func (s *WorkflowService) Replace(ctx context.Context, userID uint, request models.WorkflowRequest) (*models.Workflow, error) {
+	workflow := request.ToWorkflow(userID)
+	if err := workflow.Validate(); err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		if err := checkStatusesInUse(ctx, repos.Tasks, userID, workflow); err != nil {
+			return err
+		}
+		if err := repos.Workflows.Replace(ctx, userID, workflow); err != nil {
+			return err
+		}
+		return syncStatusTimes(ctx, repos.Tasks, userID, workflow)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// Reset borra el flujo propio del usuario y retorna el flujo por defecto, ajustando a él
+// el inicio y la finalización de las tareas
+func (s *WorkflowService) Reset(ctx context.Context, userID uint) (*models.Workflow, error) {
+	workflow := models.DefaultWorkflow()
+	err := s.repos.Tx.WithinTransaction(ctx, func(repos repositories.Repositories) error {
+		if err := checkStatusesInUse(ctx, repos.Tasks, userID, workflow); err != nil {
+			return err
+		}
+		if err := repos.Workflows.Delete(ctx, userID); err != nil {
+			return err
+		}
+		return syncStatusTimes(ctx, repos.Tasks, userID, workflow)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// loadWorkflow retorna el flujo propio del usuario o, si no tiene, el flujo por defecto
+func loadWorkflow(ctx context.Context, workflows repositories.WorkflowRepository, userID uint) (*models.Workflow, error) {
+	workflow, err := workflows.FindByUser(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.DefaultWorkflow(), nil
+	}
+	return workflow, err
+}
+
+// checkStatusesInUse verifica que todas las tareas del usuario estén en estados del flujo
+func checkStatusesInUse(ctx context.Context, tasks repositories.TaskRepository, userID uint, workflow *models.Workflow) error {
+	statuses, err := tasks.StatusesInUse(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, status := range statuses {
+		if !workflow.Has(status) {
+			missing = append(missing, status)
+		}
+	}
+	if len(missing) > 0 {
+		return &StatusInUseError{Statuses: missing}
+	}
+	return nil
+}
+
+// syncStatusTimes ajusta el inicio y la finalización de las tareas del usuario al flujo,
+// igual que Task.RecordStatusChange al cambiar de estado
+func syncStatusTimes(ctx context.Context, tasks repositories.TaskRepository, userID uint, workflow *models.Workflow) error {
+	return tasks.SyncStatusTimes(ctx, userID, workflow.Initial(), workflow.DoneKeys(), time.Now())
+}
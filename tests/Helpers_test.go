@@ -2,9 +2,11 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"go-task-manager-mvc/config"
-	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/repositories"
 	"go-task-manager-mvc/routes"
 	"log"
 	"net/http"
@@ -50,19 +52,44 @@ func init() {
 
 // setupTestDB inicializa la base de datos de prueba
 func setupTestDB() {
-	config.ConnectDB()
-	models.MigrateModels()
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.ConnectDB(cfg.Database); err != nil {
+		log.Fatal(err)
+	}
+	if err := config.LoadJWTKeys(cfg.JWT); err != nil {
+		log.Fatal(err)
+	}
+	if err := config.LoadPasswordSettings(cfg.Password); err != nil {
+		log.Fatal(err)
+	}
+	migrator, err := migrations.New(config.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // setupRouter configura el router para tests
 func setupRouter() *gin.Engine {
 	r := gin.Default()
-	routes.SetupRoutes(r)
+	routes.SetupRoutes(r, repositories.NewGormRepositories(config.DB))
 	return r
 }
 
 // cleanupTestData limpia los datos de prueba
 func cleanupTestData() {
+	config.DB.Exec("DELETE FROM idempotency_keys WHERE user_id IN (SELECT id FROM users WHERE username LIKE '%testuser%')")
+	config.DB.Exec("DELETE FROM api_keys WHERE name LIKE '%TEST%'")
+	config.DB.Exec("DELETE FROM user_identities WHERE email LIKE '%testuser%'")
+	config.DB.Exec("DELETE FROM sessions WHERE user_id IN (SELECT id FROM users WHERE username LIKE '%testuser%')")
+	config.DB.Exec("DELETE FROM workflow_transitions WHERE user_id IN (SELECT id FROM users WHERE username LIKE '%testuser%')")
+	config.DB.Exec("DELETE FROM workflow_statuses WHERE user_id IN (SELECT id FROM users WHERE username LIKE '%testuser%')")
+	config.DB.Exec("DELETE FROM task_status_transitions WHERE task_id IN (SELECT id FROM tasks WHERE title LIKE '%TEST%')")
 	config.DB.Exec("DELETE FROM tasks WHERE title LIKE '%TEST%'")
 	config.DB.Exec("DELETE FROM users WHERE username LIKE '%testuser%'")
 }
@@ -72,7 +99,7 @@ func createTestUser(t *testing.T, router *gin.Engine, username string) TestUser
 	user := TestUser{
 		Username: username,
 		Email:    username + "@test.com",
-		Password: "password123",
+		Password: "Tareas-Prueba-2024",
 	}
 
 	// Registrar usuario
@@ -120,20 +147,44 @@ func createTestUser(t *testing.T, router *gin.Engine, username string) TestUser
 	return user
 }
 
-// makeAuthenticatedRequest realiza una petición autenticada
-func makeAuthenticatedRequest(method, url string, token string, body interface{}) (*httptest.ResponseRecorder, *http.Request) {
+// makeAuthenticatedRequest prepara una petición con el token indicado (sin Authorization si está vacío).
+// Un body string se envía tal cual, para poder probar cuerpos mal formados; cualquier otro valor
+// se codifica en JSON. headers son pares nombre, valor; los valores vacíos se omiten
+func makeAuthenticatedRequest(method, url string, token string, body interface{}, headers ...string) (*httptest.ResponseRecorder, *http.Request) {
 	var reqBody *bytes.Buffer
-	if body != nil {
+	switch body := body.(type) {
+	case nil:
+		reqBody = bytes.NewBuffer([]byte{})
+	case string:
+		reqBody = bytes.NewBufferString(body)
+	default:
 		jsonBody, _ := json.Marshal(body)
 		reqBody = bytes.NewBuffer(jsonBody)
-	} else {
-		reqBody = bytes.NewBuffer([]byte{})
 	}
 
 	req, _ := http.NewRequest(method, url, reqBody)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		if headers[i+1] != "" {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+	}
 	w := httptest.NewRecorder()
 
 	return w, req
 }
+
+// serveRequest envía al router la petición de makeAuthenticatedRequest y retorna la respuesta
+func serveRequest(router http.Handler, method, url string, token string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	w, req := makeAuthenticatedRequest(method, url, token, body, headers...)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// makeAPIKeyRequest realiza una petición autenticada con una API key en la cabecera indicada
+func makeAPIKeyRequest(method, url string, header string, value string, body interface{}) (*httptest.ResponseRecorder, *http.Request) {
+	return makeAuthenticatedRequest(method, url, "", body, header, value)
+}
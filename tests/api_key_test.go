@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestAPIKey crea una API key con los scopes indicados y retorna la clave y su ID
+func createTestAPIKey(t *testing.T, token string, name string, scopes []string) (string, int) {
+	w, req := makeAuthenticatedRequest("POST", "/api/api-keys", token, map[string]interface{}{
+		"name":   name,
+		"scopes": scopes,
+	})
+	setupRouter().ServeHTTP(w, req)
+
+	if !assert.Equal(t, http.StatusCreated, w.Code, w.Body.String()) {
+		return "", 0
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	apiKey := response["api_key"].(map[string]interface{})
+	return response["key"].(string), int(apiKey["id"].(float64))
+}
+
+func TestAPIKeys(t *testing.T) {
+	setupTestDB()
+	router := setupRouter()
+	defer cleanupTestData()
+
+	testUser := createTestUser(t, router, "testuser_api_keys")
+
+	t.Run("Crear API key exitosamente", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("POST", "/api/api-keys", testUser.Token, map[string]interface{}{
+			"name":   "TEST: CI",
+			"scopes": []string{"tasks:read", "tasks:write"},
+		})
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Regexp(t, `^tk_[0-9a-f]{8}_[0-9a-f]{64}$`, response["key"])
+
+		apiKey := response["api_key"].(map[string]interface{})
+		assert.Equal(t, "TEST: CI", apiKey["name"])
+		assert.NotContains(t, apiKey, "key_hash")
+	})
+
+	t.Run("Crear API key con scope inválido", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("POST", "/api/api-keys", testUser.Token, map[string]interface{}{
+			"name":   "TEST: Scope inválido",
+			"scopes": []string{"api_keys:manage"},
+		})
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Crear API key con expiración pasada", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("POST", "/api/api-keys", testUser.Token, map[string]interface{}{
+			"name":       "TEST: Expirada",
+			"scopes":     []string{"tasks:read"},
+			"expires_at": "2020-01-01T00:00:00Z",
+		})
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Autenticar con X-API-Key y Authorization ApiKey", func(t *testing.T) {
+		key, _ := createTestAPIKey(t, testUser.Token, "TEST: Lectura", []string{"tasks:read"})
+
+		w, req := makeAPIKeyRequest("GET", "/api/tasks", "X-API-Key", key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, req = makeAPIKeyRequest("GET", "/api/tasks", "Authorization", "ApiKey "+key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Registrar último uso", func(t *testing.T) {
+		key, id := createTestAPIKey(t, testUser.Token, "TEST: Último uso", []string{"tasks:read"})
+
+		w, req := makeAPIKeyRequest("GET", "/api/tasks", "X-API-Key", key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var apiKey models.APIKey
+		config.DB.First(&apiKey, id)
+		assert.NotNil(t, apiKey.LastUsedAt)
+	})
+
+	t.Run("Scope insuficiente", func(t *testing.T) {
+		key, _ := createTestAPIKey(t, testUser.Token, "TEST: Solo lectura", []string{"tasks:read"})
+
+		w, req := makeAPIKeyRequest("POST", "/api/tasks", "X-API-Key", key, map[string]interface{}{
+			"title": "TEST: Tarea con API key de lectura",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Crear tarea con scope de escritura", func(t *testing.T) {
+		key, _ := createTestAPIKey(t, testUser.Token, "TEST: Escritura", []string{"tasks:write"})
+
+		w, req := makeAPIKeyRequest("POST", "/api/tasks", "X-API-Key", key, map[string]interface{}{
+			"title": "TEST: Tarea con API key",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Una API key no puede gestionar API keys", func(t *testing.T) {
+		key, _ := createTestAPIKey(t, testUser.Token, "TEST: Sin gestión", []string{"tasks:read", "tasks:write"})
+
+		w, req := makeAPIKeyRequest("GET", "/api/api-keys", "X-API-Key", key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("API key revocada", func(t *testing.T) {
+		key, id := createTestAPIKey(t, testUser.Token, "TEST: Revocada", []string{"tasks:read"})
+
+		w, req := makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/api-keys/%d", id), testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, req = makeAPIKeyRequest("GET", "/api/tasks", "X-API-Key", key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("API key expirada", func(t *testing.T) {
+		key, id := createTestAPIKey(t, testUser.Token, "TEST: Expira", []string{"tasks:read"})
+		config.DB.Model(&models.APIKey{}).Where("id = ?", id).Update("expires_at", time.Now().Add(-time.Hour))
+
+		w, req := makeAPIKeyRequest("GET", "/api/tasks", "X-API-Key", key, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("API key con secreto incorrecto", func(t *testing.T) {
+		key, _ := createTestAPIKey(t, testUser.Token, "TEST: Alterada", []string{"tasks:read"})
+		tampered := key[:len(key)-4] + "0000"
+
+		w, req := makeAPIKeyRequest("GET", "/api/tasks", "X-API-Key", tampered, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Listar API keys sin exponer secretos", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("GET", "/api/api-keys", testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Greater(t, response["count"], float64(0))
+		assert.NotContains(t, w.Body.String(), "key_hash")
+		assert.NotContains(t, w.Body.String(), `"key"`)
+	})
+
+	t.Run("No revocar API key de otro usuario", func(t *testing.T) {
+		_, id := createTestAPIKey(t, testUser.Token, "TEST: Ajena", []string{"tasks:read"})
+		testUser2 := createTestUser(t, router, "testuser_api_keys_2")
+
+		w, req := makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/api-keys/%d", id), testUser2.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// collidingAPIKeys simula que los primeros prefijos generados ya están en uso
+type collidingAPIKeys struct {
+	repositories.APIKeyRepository
+	collisions int
+}
+
+func (r *collidingAPIKeys) Create(ctx context.Context, apiKey *models.APIKey) error {
+	if r.collisions > 0 {
+		r.collisions--
+		return repositories.ErrDuplicate
+	}
+	return r.APIKeyRepository.Create(ctx, apiKey)
+}
+
+func TestAPIKeyPrefixCollision(t *testing.T) {
+	repos, _ := newMemoryRepositories()
+	request := models.APIKeyCreateRequest{Name: "TEST", Scopes: []string{"tasks:read"}}
+
+	t.Run("Genera otro prefijo si ya está en uso", func(t *testing.T) {
+		service := services.NewAPIKeyService(&collidingAPIKeys{APIKeyRepository: repos.APIKeys, collisions: 2})
+		key, apiKey, err := service.Create(context.Background(), 1, request)
+		require.NoError(t, err)
+		assert.Contains(t, key, apiKey.Prefix)
+	})
+
+	t.Run("Se rinde tras varios intentos", func(t *testing.T) {
+		service := services.NewAPIKeyService(&collidingAPIKeys{APIKeyRepository: repos.APIKeys, collisions: 10})
+		_, _, err := service.Create(context.Background(), 1, request)
+		assert.ErrorIs(t, err, repositories.ErrDuplicate)
+	})
+}
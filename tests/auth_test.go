@@ -19,7 +19,7 @@ func TestUserRegistration(t *testing.T) {
 		userData := map[string]string{
 			"username": "testuser_register",
 			"email":    "testuser_register@test.com",
-			"password": "password123",
+			"password": "Tareas-Prueba-2024",
 		}
 
 		body, _ := json.Marshal(userData)
@@ -44,7 +44,7 @@ func TestUserRegistration(t *testing.T) {
 		userData := map[string]string{
 			"username": "testuser_duplicate2",
 			"email":    "testuser_duplicate@test.com",
-			"password": "password123",
+			"password": "Tareas-Prueba-2024",
 		}
 
 		body, _ := json.Marshal(userData)
@@ -53,7 +53,10 @@ func TestUserRegistration(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "email_taken", response["code"])
 	})
 
 	t.Run("Registro con datos inválidos", func(t *testing.T) {
@@ -105,7 +108,7 @@ func TestUserLogin(t *testing.T) {
 	t.Run("Login con email inexistente", func(t *testing.T) {
 		loginData := map[string]string{
 			"email":    "noexiste@test.com",
-			"password": "password123",
+			"password": "Tareas-Prueba-2024",
 		}
 
 		body, _ := json.Marshal(loginData)
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// boardResponse es la respuesta de GET /api/board
+type boardResponse struct {
+	Columns []struct {
+		Status   string `json:"status"`
+		Label    string `json:"label"`
+		WIPLimit *int   `json:"wip_limit"`
+		Count    int    `json:"count"`
+		Tasks    []struct {
+			ID       uint   `json:"id"`
+			Title    string `json:"title"`
+			Position int    `json:"position"`
+		} `json:"tasks"`
+	} `json:"columns"`
+}
+
+// columnTitles retorna los títulos de las tareas de cada columna del tablero, en orden
+func columnTitles(t *testing.T, w *httptest.ResponseRecorder) map[string][]string {
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var board boardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &board))
+
+	titles := make(map[string][]string, len(board.Columns))
+	for _, column := range board.Columns {
+		titles[column.Status] = []string{}
+		for _, task := range column.Tasks {
+			titles[column.Status] = append(titles[column.Status], task.Title)
+		}
+	}
+	return titles
+}
+
+func TestBoard(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_board")
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		return serveRequest(router, method, url, user.Token, body, "Accept-Language", "es")
+	}
+	move := func(id uint, body string) *httptest.ResponseRecorder {
+		return request(http.MethodPost, fmt.Sprintf("/api/tasks/%d/move", id), body)
+	}
+
+	w := request(http.MethodPut, "/api/me/workflow", `{"statuses":[
+		{"key":"todo","name":"Por hacer"},
+		{"key":"doing","name":"En curso","wip_limit":2},
+		{"key":"done","name":"Hecha","done":true}
+	]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	ids := make(map[string]uint)
+	for _, title := range []string{"A", "B", "C"} {
+		w := request(http.MethodPost, "/api/tasks", fmt.Sprintf(`{"title":"TEST %s"}`, title))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		ids[title] = uint(decodeTask(t, w)["id"].(float64))
+	}
+
+	t.Run("Las tareas nuevas se añaden al final de su columna", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/board", "")
+		var board boardResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &board))
+		require.Len(t, board.Columns, 3)
+		assert.Equal(t, "Por hacer", board.Columns[0].Label)
+		assert.Equal(t, 3, board.Columns[0].Count)
+		assert.Nil(t, board.Columns[0].WIPLimit)
+		require.NotNil(t, board.Columns[1].WIPLimit)
+		assert.Equal(t, 2, *board.Columns[1].WIPLimit)
+
+		assert.Equal(t, map[string][]string{
+			"todo":  {"TEST A", "TEST B", "TEST C"},
+			"doing": {},
+			"done":  {},
+		}, columnTitles(t, w))
+	})
+
+	t.Run("Reordenar dentro de la columna", func(t *testing.T) {
+		w := move(ids["C"], `{"position":0}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"TEST C", "TEST A", "TEST B"}, columnTitles(t, request(http.MethodGet, "/api/board", ""))["todo"])
+	})
+
+	t.Run("Mover de columna cambia estado y posición a la vez", func(t *testing.T) {
+		w := move(ids["A"], `{"status":"doing"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		task := decodeTask(t, w)
+		assert.Equal(t, "doing", task["status"])
+		assert.Equal(t, float64(0), task["position"])
+		// La respuesta tiene los mismos campos que el listado de tareas
+		assert.Equal(t, "TEST A", task["title"])
+		assert.Equal(t, "En curso", task["status_label"])
+		assert.Contains(t, task, "cycle_time_seconds")
+
+		w = move(ids["B"], `{"status":"doing","position":0}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		titles := columnTitles(t, request(http.MethodGet, "/api/board", ""))
+		assert.Equal(t, []string{"TEST C"}, titles["todo"])
+		assert.Equal(t, []string{"TEST B", "TEST A"}, titles["doing"])
+	})
+
+	t.Run("Rechazar movimientos que superan el límite de la columna", func(t *testing.T) {
+		w := move(ids["C"], `{"status":"doing"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "wip_limit_exceeded", response["code"])
+		assert.Equal(t, "doing", response["task_status"])
+		assert.Equal(t, float64(2), response["wip_limit"])
+
+		// Cambiar el estado con PUT también respeta el límite
+		w = request(http.MethodPut, fmt.Sprintf("/api/tasks/%d", ids["C"]), `{"status":"doing"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		// Reordenar dentro de una columna llena sí está permitido
+		w = move(ids["A"], `{"position":0}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"TEST C"}, columnTitles(t, request(http.MethodGet, "/api/board", ""))["todo"])
+	})
+
+	t.Run("Validar el movimiento", func(t *testing.T) {
+		w := move(ids["C"], `{"status":"archivada"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []string{"status:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+
+		w = move(ids["C"], `{"position":-1}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []string{"position:min"}, fieldCodes(decodeProblem(t, w).Errors))
+
+		w = move(9999, `{"status":"done"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBoardWithDatabase(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_board_db")
+
+	var ids []uint
+	for _, title := range []string{"TEST uno", "TEST dos", "TEST tres"} {
+		w, req := makeAuthenticatedRequest("POST", "/api/tasks", user.Token, map[string]interface{}{"title": title})
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		ids = append(ids, uint(decodeTask(t, w)["id"].(float64)))
+	}
+
+	w, req := makeAuthenticatedRequest("POST", fmt.Sprintf("/api/tasks/%d/move", ids[2]), user.Token,
+		map[string]interface{}{"status": models.TaskStatusPending, "position": 0})
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var tasks []models.Task
+	require.NoError(t, config.DB.Where("user_id = ?", user.ID).Order("position").Find(&tasks).Error)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, []uint{ids[2], ids[0], ids[1]}, []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID})
+	assert.Equal(t, []int{0, 1, 2}, []int{tasks[0].Position, tasks[1].Position, tasks[2].Position})
+
+	w, req = makeAuthenticatedRequest("GET", "/api/board", user.Token, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, []string{"TEST tres", "TEST uno", "TEST dos"}, columnTitles(t, w)[models.TaskStatusPending])
+
+	// positions retorna los IDs y posiciones de las tareas de una columna
+	positions := func(status string) map[uint]int {
+		var tasks []models.Task
+		require.NoError(t, config.DB.Where("user_id = ? AND status = ?", user.ID, status).Find(&tasks).Error)
+		result := make(map[uint]int, len(tasks))
+		for _, task := range tasks {
+			result[task.ID] = task.Position
+		}
+		return result
+	}
+
+	// Eliminar una tarea y sacarla de su columna cierran el hueco que deja
+	w, req = makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/tasks/%d", ids[0]), user.Token, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[uint]int{ids[2]: 0, ids[1]: 1}, positions(models.TaskStatusPending))
+
+	w, req = makeAuthenticatedRequest("POST", fmt.Sprintf("/api/tasks/%d/move", ids[2]), user.Token,
+		map[string]interface{}{"status": models.TaskStatusInProgress})
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[uint]int{ids[1]: 0}, positions(models.TaskStatusPending))
+	assert.Equal(t, map[uint]int{ids[2]: 0}, positions(models.TaskStatusInProgress))
+}
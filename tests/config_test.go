@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile guarda un archivo de configuración temporal
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// keepAppConfig restaura config.App al terminar el test
+func keepAppConfig(t *testing.T) {
+	previous := config.App
+	t.Cleanup(func() { config.App = previous })
+}
+
+func TestConfigLoad(t *testing.T) {
+	keepAppConfig(t)
+	// Valores obligatorios, para no depender del entorno en el que se ejecutan los tests
+	t.Setenv("DB_USER", "taskuser")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_NAME", "taskdb")
+	t.Setenv("JWT_SECRET", "secreto-de-prueba")
+
+	t.Run("Valores por defecto y variables de entorno", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", "")
+		t.Setenv("PORT", "9090")
+		t.Setenv("PASSWORD_REQUIRE_UPPER", "true")
+		t.Setenv("OIDC_SCOPES", "openid email")
+
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		assert.Equal(t, "9090", cfg.Server.Port)
+		assert.Equal(t, 6, cfg.Password.MinLength)
+		assert.True(t, cfg.Password.RequireUpper)
+		assert.Equal(t, []string{"openid", "email"}, cfg.OIDC.Scopes)
+		assert.Equal(t, "go-task-api", cfg.JWT.Issuer)
+		assert.Same(t, cfg, config.App)
+	})
+
+	t.Run("Archivo YAML", func(t *testing.T) {
+		t.Setenv("PORT", "")
+		t.Setenv("JWT_ISSUER", "")
+		path := writeConfigFile(t, "config.yaml", `
+server:
+  port: "7070"
+jwt:
+  issuer: desde-yaml
+  verification_key_files: [a.pem, b.pem]
+password:
+  min_length: 10
+`)
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "7070", cfg.Server.Port)
+		assert.Equal(t, "desde-yaml", cfg.JWT.Issuer)
+		assert.Equal(t, []string{"a.pem", "b.pem"}, cfg.JWT.VerificationKeyFiles)
+		assert.Equal(t, 10, cfg.Password.MinLength)
+		// Lo que no aparece en el archivo conserva su valor por defecto
+		assert.Equal(t, "argon2id", cfg.Password.HashAlgorithm)
+	})
+
+	t.Run("Archivo TOML indicado en CONFIG_FILE", func(t *testing.T) {
+		t.Setenv("PORT", "")
+		path := writeConfigFile(t, "config.toml", `
+[server]
+port = "6060"
+
+[password]
+min_length = 12
+require_symbol = true
+`)
+		t.Setenv("CONFIG_FILE", path)
+
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		assert.Equal(t, "6060", cfg.Server.Port)
+		assert.Equal(t, 12, cfg.Password.MinLength)
+		assert.True(t, cfg.Password.RequireSymbol)
+	})
+
+	t.Run("Las variables de entorno tienen prioridad sobre el archivo", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "server:\n  port: \"7070\"\n")
+		t.Setenv("PORT", "9191")
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "9191", cfg.Server.Port)
+	})
+
+	t.Run("Falla sin JWT_SECRET", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", "")
+		t.Setenv("JWT_SECRET", "")
+		t.Setenv("JWT_PRIVATE_KEY_FILE", "")
+
+		_, err := config.Load("")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "JWT_SECRET")
+		}
+	})
+
+	t.Run("Reporta todos los valores inválidos", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", "")
+		t.Setenv("PORT", "abc")
+		t.Setenv("PASSWORD_HASH_ALGORITHM", "md5")
+
+		_, err := config.Load("")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "PORT")
+			assert.Contains(t, err.Error(), "PASSWORD_HASH_ALGORITHM")
+		}
+
+		t.Setenv("PORT", "")
+		t.Setenv("PASSWORD_HASH_ALGORITHM", "")
+		t.Setenv("PASSWORD_MIN_LENGTH", "ocho")
+		_, err = config.Load("")
+		assert.Error(t, err)
+
+		t.Setenv("PASSWORD_MIN_LENGTH", "")
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy.interno")
+		t.Setenv("RATE_LIMIT_AUTH_REQUESTS", "0")
+		_, err = config.Load("")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "proxy.interno")
+			assert.Contains(t, err.Error(), "RATE_LIMIT_AUTH_REQUESTS")
+		}
+	})
+
+	t.Run("Duraciones desde archivo y variables de entorno", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", "")
+		t.Setenv("SERVER_WRITE_TIMEOUT", "45s")
+		path := writeConfigFile(t, "config.yaml", "server:\n  read_timeout: 5s\n  shutdown_timeout: 1m30s\n")
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout.Std())
+		assert.Equal(t, 45*time.Second, cfg.Server.WriteTimeout.Std())
+		assert.Equal(t, 90*time.Second, cfg.Server.ShutdownTimeout.Std())
+		assert.Equal(t, 60*time.Second, cfg.Server.IdleTimeout.Std())
+		assert.Contains(t, cfg.String(), "shutdown_timeout: 1m30s")
+
+		t.Setenv("SERVER_WRITE_TIMEOUT", "pronto")
+		_, err = config.Load(path)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "SERVER_WRITE_TIMEOUT")
+		}
+	})
+
+	t.Run("Formato de archivo no soportado", func(t *testing.T) {
+		path := writeConfigFile(t, "config.json", "{}")
+
+		_, err := config.Load(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("Volcado redactado", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Database.Password = "db-secreta"
+		cfg.JWT.Secret = "jwt-secreto"
+		cfg.OIDC.ClientSecret = "oidc-secreto"
+		cfg.Database.User = "taskuser"
+
+		dump := cfg.String()
+		assert.NotContains(t, dump, "db-secreta")
+		assert.NotContains(t, dump, "jwt-secreto")
+		assert.NotContains(t, dump, "oidc-secreto")
+		assert.Contains(t, dump, "[REDACTED]")
+		assert.Contains(t, dump, "taskuser")
+
+		// La configuración original no se modifica
+		assert.Equal(t, "jwt-secreto", cfg.JWT.Secret)
+	})
+}
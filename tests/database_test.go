@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestDatabaseTimeouts(t *testing.T) {
+	setupTestDB()
+	defer cleanupTestData()
+
+	t.Run("El pool usa los límites de la configuración", func(t *testing.T) {
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+
+		sqlDB, err := config.DB.DB()
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Database.MaxOpenConns, sqlDB.Stats().MaxOpenConnections)
+	})
+
+	t.Run("Consulta con el plazo vencido retorna ErrQueryTimeout", func(t *testing.T) {
+		repos := repositories.NewGormRepositories(config.DB)
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := repos.Tasks.FindByUser(ctx, 1, "")
+		assert.ErrorIs(t, err, repositories.ErrQueryTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Petición que supera el plazo responde 504", func(t *testing.T) {
+		router := gin.New()
+		router.Use(middleware.QueryDeadline(time.Nanosecond))
+		routes.SetupRoutes(router, repositories.NewGormRepositories(config.DB))
+
+		w, req := makeAuthenticatedRequest("POST", "/api/login", "", map[string]string{
+			"email":    "testuser_deadline@test.com",
+			"password": "Tareas-Prueba-2024",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("Base de datos caída responde 503", func(t *testing.T) {
+		// Nada escucha en el puerto 1: cada consulta falla al conectar
+		db, err := gorm.Open(mysql.New(mysql.Config{
+			DSN:                       "user:pass@tcp(127.0.0.1:1)/taskdb?timeout=1s",
+			SkipInitializeWithVersion: true,
+		}), &gorm.Config{DisableAutomaticPing: true})
+		require.NoError(t, err)
+		repos := repositories.NewGormRepositories(db)
+
+		_, err = repos.Users.FindByEmail(context.Background(), "testuser_down@test.com")
+		assert.ErrorIs(t, err, repositories.ErrDatabaseUnavailable)
+
+		router := gin.New()
+		routes.SetupRoutes(router, repos)
+
+		w, req := makeAuthenticatedRequest("POST", "/api/login", "", map[string]string{
+			"email":    "testuser_down@test.com",
+			"password": "Tareas-Prueba-2024",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		w, req = makeAuthenticatedRequest("GET", "/api/tasks", "", nil)
+		req.Header.Set("X-API-Key", "tk_inexistente_0000")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, "una caída no debe responderse como credenciales inválidas")
+		assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	})
+}
@@ -0,0 +1,663 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// memoryStore guarda los datos en memoria para probar servicios y handlers sin base de datos
+type memoryStore struct {
+	mu         sync.Mutex
+	nextID     uint
+	users      map[uint]models.User
+	tasks      map[uint]models.Task
+	apiKeys    map[uint]models.APIKey
+	sessions   map[uint]models.Session
+	identities map[uint]models.UserIdentity
+	idempotent map[uint]models.IdempotencyKey
+	workflows  map[uint]models.Workflow
+	// transitions son los cambios de estado de las tareas en orden de registro
+	transitions []models.TaskStatusTransition
+}
+
+// newMemoryRepositories crea repositorios en memoria que comparten un mismo almacén
+func newMemoryRepositories() (repositories.Repositories, *memoryStore) {
+	store := &memoryStore{
+		users:      make(map[uint]models.User),
+		tasks:      make(map[uint]models.Task),
+		apiKeys:    make(map[uint]models.APIKey),
+		sessions:   make(map[uint]models.Session),
+		identities: make(map[uint]models.UserIdentity),
+		idempotent: make(map[uint]models.IdempotencyKey),
+		workflows:  make(map[uint]models.Workflow),
+	}
+	repos := repositories.Repositories{
+		Users:       &memoryUserRepository{store},
+		Tasks:       &memoryTaskRepository{store},
+		APIKeys:     &memoryAPIKeyRepository{store},
+		Sessions:    &memorySessionRepository{store},
+		Identities:  &memoryIdentityRepository{store},
+		Idempotency: &memoryIdempotencyRepository{store},
+		Workflows:   &memoryWorkflowRepository{store},
+	}
+	repos.Tx = &memoryTransactor{repos: repos}
+	return repos, store
+}
+
+func (s *memoryStore) id() uint {
+	s.nextID++
+	return s.nextID
+}
+
+// memoryTransactor ejecuta fn sin aislamiento ni rollback
+type memoryTransactor struct {
+	repos repositories.Repositories
+}
+
+func (t *memoryTransactor) WithinTransaction(ctx context.Context, fn func(repos repositories.Repositories) error) error {
+	return fn(t.repos)
+}
+
+type memoryUserRepository struct{ *memoryStore }
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryUserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.users {
+		if existing.Username == user.Username || existing.Email == user.Email {
+			return repositories.ErrDuplicate
+		}
+	}
+	user.ID = r.id()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user := r.users[userID]
+	user.Password = hash
+	r.users[userID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateLanguage(ctx context.Context, userID uint, language string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	user.Language = language
+	r.users[userID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateTimezone(ctx context.Context, userID uint, timezone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[userID]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	user.Timezone = timezone
+	r.users[userID] = user
+	return nil
+}
+
+// Lock no hace nada: el almacén en memoria no tiene transacciones que aislar
+func (r *memoryUserRepository) Lock(ctx context.Context, userID uint) error {
+	return nil
+}
+
+type memoryTaskRepository struct{ *memoryStore }
+
+func (r *memoryTaskRepository) FindByUser(ctx context.Context, userID uint, status string) ([]models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tasks := []models.Task{}
+	for _, task := range r.tasks {
+		if task.UserID == userID && (status == "" || task.Status == status) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID > tasks[j].ID })
+	return tasks, nil
+}
+
+func (r *memoryTaskRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.tasks[id]
+	if !ok || task.UserID != userID {
+		return nil, repositories.ErrNotFound
+	}
+	return &task, nil
+}
+
+func (r *memoryTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.ID = r.id()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *memoryTaskRepository) Update(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.UpdatedAt = time.Now()
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *memoryTaskRepository) Delete(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tasks, task.ID)
+	return nil
+}
+
+func (r *memoryTaskRepository) CountAll(ctx context.Context, now time.Time) (repositories.TaskCounts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := repositories.TaskCounts{ByStatus: make(map[string]int64)}
+	for _, task := range r.tasks {
+		if models.IsValidTaskStatus(task.Status) {
+			counts.ByStatus[task.Status]++
+		} else {
+			counts.ByStatus[repositories.OtherTaskStatus]++
+		}
+		workflow, ok := r.workflows[task.UserID]
+		if !ok {
+			workflow = *models.DefaultWorkflow()
+		}
+		if task.DueDate != nil && task.DueDate.Before(now) && !workflow.IsDone(task.Status) {
+			counts.Overdue++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryTaskRepository) Stats(ctx context.Context, query repositories.TaskStatsQuery) (repositories.TaskStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	done := make(map[string]bool, len(query.DoneStatuses))
+	for _, status := range query.DoneStatuses {
+		done[status] = true
+	}
+	// bucket retorna el grupo de t según los límites, como bucketExpr en SQL
+	bucket := func(t time.Time, bounds []time.Time) int {
+		for i, bound := range bounds {
+			if t.Before(bound) {
+				return i
+			}
+		}
+		return len(bounds)
+	}
+	periods := max(len(query.Periods)-1, 0)
+	inRange := func(t time.Time) bool {
+		return periods > 0 && !t.Before(query.Periods[0]) && t.Before(query.Periods[periods])
+	}
+
+	stats := repositories.TaskStats{
+		ByStatus:  make(map[string]int64),
+		Created:   make([]int64, periods),
+		Completed: make([]int64, periods),
+		Due:       make([]int64, len(query.DueBounds)+1),
+	}
+	var completed, started int
+	var seconds, cycleSeconds float64
+	for _, task := range r.tasks {
+		if task.UserID != query.UserID {
+			continue
+		}
+		stats.ByStatus[task.Status]++
+		if inRange(task.CreatedAt) {
+			stats.Created[bucket(task.CreatedAt, query.Periods[1:periods])]++
+		}
+		switch {
+		case done[task.Status]:
+			if task.CompletedAt == nil {
+				continue
+			}
+			completed++
+			seconds += task.CompletedAt.Sub(task.CreatedAt).Seconds()
+			if task.StartedAt != nil {
+				started++
+				cycleSeconds += task.CompletedAt.Sub(*task.StartedAt).Seconds()
+			}
+			if inRange(*task.CompletedAt) {
+				stats.Completed[bucket(*task.CompletedAt, query.Periods[1:periods])]++
+			}
+		case task.DueDate == nil:
+			stats.NoDueDate++
+		default:
+			stats.Due[bucket(*task.DueDate, query.DueBounds)]++
+		}
+	}
+	if completed > 0 {
+		average := seconds / float64(completed)
+		stats.AvgCompletionSeconds = &average
+	}
+	if started > 0 {
+		average := cycleSeconds / float64(started)
+		stats.AvgCycleSeconds = &average
+	}
+	return stats, nil
+}
+
+func (r *memoryTaskRepository) RecordTransition(ctx context.Context, transition *models.TaskStatusTransition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transition.ID = r.id()
+	r.transitions = append(r.transitions, *transition)
+	return nil
+}
+
+func (r *memoryTaskRepository) FindTransitions(ctx context.Context, taskID uint) ([]models.TaskStatusTransition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transitions := []models.TaskStatusTransition{}
+	for _, transition := range r.transitions {
+		if transition.TaskID == taskID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+func (r *memoryTaskRepository) FindColumn(ctx context.Context, userID uint, status string) ([]models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tasks := []models.Task{}
+	for _, task := range r.tasks {
+		if task.UserID == userID && task.Status == status {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+func (r *memoryTaskRepository) UpdatePosition(ctx context.Context, id uint, position int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task := r.tasks[id]
+	task.Position = position
+	r.tasks[id] = task
+	return nil
+}
+
+func (r *memoryTaskRepository) SyncStatusTimes(ctx context.Context, userID uint, initial string, done []string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, task := range r.tasks {
+		if task.UserID != userID {
+			continue
+		}
+		switch {
+		case task.Status == initial:
+			task.StartedAt = nil
+		case task.StartedAt == nil:
+			task.StartedAt = &now
+		}
+		switch {
+		case !slices.Contains(done, task.Status):
+			task.CompletedAt = nil
+		case task.CompletedAt == nil:
+			task.CompletedAt = &now
+		}
+		r.tasks[id] = task
+	}
+	return nil
+}
+
+func (r *memoryTaskRepository) StatusesInUse(ctx context.Context, userID uint) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := make(map[string]bool)
+	statuses := []string{}
+	for _, task := range r.tasks {
+		if task.UserID == userID && !seen[task.Status] {
+			seen[task.Status] = true
+			statuses = append(statuses, task.Status)
+		}
+	}
+	sort.Strings(statuses)
+	return statuses, nil
+}
+
+type memoryWorkflowRepository struct{ *memoryStore }
+
+func (r *memoryWorkflowRepository) FindByUser(ctx context.Context, userID uint) (*models.Workflow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	workflow, ok := r.workflows[userID]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &workflow, nil
+}
+
+func (r *memoryWorkflowRepository) Replace(ctx context.Context, userID uint, workflow *models.Workflow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workflows[userID] = *workflow
+	return nil
+}
+
+func (r *memoryWorkflowRepository) Delete(ctx context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.workflows, userID)
+	return nil
+}
+
+type memoryAPIKeyRepository struct{ *memoryStore }
+
+func (r *memoryAPIKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apiKey.ID = r.id()
+	apiKey.CreatedAt = time.Now()
+	r.apiKeys[apiKey.ID] = *apiKey
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, apiKey := range r.apiKeys {
+		if apiKey.Prefix == prefix {
+			apiKey.User = r.users[apiKey.UserID]
+			return &apiKey, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryAPIKeyRepository) FindByUser(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apiKeys := []models.APIKey{}
+	for _, apiKey := range r.apiKeys {
+		if apiKey.UserID == userID {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	sort.Slice(apiKeys, func(i, j int) bool { return apiKeys[i].ID > apiKeys[j].ID })
+	return apiKeys, nil
+}
+
+func (r *memoryAPIKeyRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apiKey, ok := r.apiKeys[id]
+	if !ok || apiKey.UserID != userID {
+		return nil, repositories.ErrNotFound
+	}
+	return &apiKey, nil
+}
+
+func (r *memoryAPIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apiKey := r.apiKeys[id]
+	apiKey.RevokedAt = &at
+	r.apiKeys[id] = apiKey
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	apiKey := r.apiKeys[id]
+	apiKey.LastUsedAt = &at
+	r.apiKeys[id] = apiKey
+	return nil
+}
+
+type memorySessionRepository struct{ *memoryStore }
+
+func (r *memorySessionRepository) Create(ctx context.Context, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.ID = r.id()
+	session.CreatedAt = time.Now()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *memorySessionRepository) FindByID(ctx context.Context, id uint) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	session.User = r.users[session.UserID]
+	return &session, nil
+}
+
+func (r *memorySessionRepository) FindByIDForUser(ctx context.Context, id uint, userID uint) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID {
+		return nil, repositories.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (r *memorySessionRepository) FindActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := []models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt) })
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	session.RevokedAt = &at
+	r.sessions[id] = session
+	return nil
+}
+
+func (r *memorySessionRepository) RevokeAllByUser(ctx context.Context, userID uint, exceptID uint, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var revoked int64
+	for id, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && id != exceptID {
+			session.RevokedAt = &at
+			r.sessions[id] = session
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memorySessionRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	session.LastSeenAt = at
+	r.sessions[id] = session
+	return nil
+}
+
+func (r *memorySessionRepository) DeleteInactive(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, session := range r.sessions {
+		if session.ExpiresAt.Before(before) || (session.RevokedAt != nil && session.RevokedAt.Before(before)) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type memoryIdentityRepository struct{ *memoryStore }
+
+func (r *memoryIdentityRepository) FindByIssuerAndSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			identity.User = r.users[identity.UserID]
+			return &identity, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *memoryIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identity.ID = r.id()
+	identity.CreatedAt = time.Now()
+	r.identities[identity.ID] = *identity
+	return nil
+}
+
+type memoryIdempotencyRepository struct{ *memoryStore }
+
+func (r *memoryIdempotencyRepository) find(userID uint, key string) (models.IdempotencyKey, bool) {
+	for _, record := range r.idempotent {
+		if record.UserID == userID && record.Key == key {
+			return record, true
+		}
+	}
+	return models.IdempotencyKey{}, false
+}
+
+func (r *memoryIdempotencyRepository) Create(ctx context.Context, key *models.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.find(key.UserID, key.Key); exists {
+		return repositories.ErrDuplicate
+	}
+	key.ID = r.id()
+	key.CreatedAt = time.Now()
+	r.idempotent[key.ID] = *key
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) FindByKey(ctx context.Context, userID uint, key string) (*models.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.find(userID, key)
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &record, nil
+}
+
+func (r *memoryIdempotencyRepository) ReplaceExpired(ctx context.Context, key *models.IdempotencyKey, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.find(key.UserID, key.Key)
+	abandoned := !record.Completed() && record.LockedUntil != nil && !record.LockedUntil.After(now)
+	if !ok || (record.ExpiresAt.After(now) && !abandoned) {
+		return false, nil
+	}
+	key.ID = record.ID
+	key.CreatedAt = now
+	r.idempotent[key.ID] = *key
+	return true, nil
+}
+
+func (r *memoryIdempotencyRepository) Complete(ctx context.Context, id uint, status int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.idempotent[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	record.StatusCode = status
+	record.ContentType = contentType
+	record.ResponseBody = body
+	r.idempotent[id] = record
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.idempotent, id)
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, record := range r.idempotent {
+		if !record.ExpiresAt.After(before) {
+			delete(r.idempotent, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"go-task-manager-mvc/buildinfo"
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/workers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idleWorker corre hasta que se cancela su contexto
+type idleWorker struct{ name string }
+
+func (w idleWorker) Name() string            { return w.name }
+func (w idleWorker) Run(ctx context.Context) { <-ctx.Done() }
+
+// probe hace un GET y decodifica la respuesta JSON
+func probe(t *testing.T, router *gin.Engine, path string) (int, map[string]interface{}) {
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+// checkStatus retorna el estado de una comprobación de /readyz
+func checkStatus(response map[string]interface{}, name string) interface{} {
+	checks := response["checks"].(map[string]interface{})
+	return checks[name].(map[string]interface{})["status"]
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	setupTestDB()
+
+	manager := workers.NewManager(idleWorker{name: "idle"})
+	router := gin.New()
+	routes.SetupHealthRoutes(router, manager)
+
+	t.Run("Healthz responde sin dependencias", func(t *testing.T) {
+		code, response := probe(t, router, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ok", response["status"])
+	})
+
+	t.Run("Readyz no está listo si los workers no arrancaron", func(t *testing.T) {
+		code, response := probe(t, router, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", response["status"])
+		assert.Equal(t, "ok", checkStatus(response, "database"))
+		assert.Equal(t, "ok", checkStatus(response, "migrations"))
+		assert.Equal(t, "error", checkStatus(response, "workers"))
+	})
+
+	manager.Start()
+	defer manager.Stop(context.Background())
+
+	t.Run("Readyz listo con base de datos, migraciones y workers", func(t *testing.T) {
+		code, response := probe(t, router, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", response["status"])
+		assert.Equal(t, "ok", checkStatus(response, "workers"))
+	})
+
+	t.Run("Readyz detecta la base de datos caída", func(t *testing.T) {
+		db := config.DB
+		config.DB = nil
+		defer func() { config.DB = db }()
+
+		code, response := probe(t, router, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "error", checkStatus(response, "database"))
+		assert.Equal(t, "error", checkStatus(response, "migrations"))
+
+		// Solo un código estable, sin el mensaje del error
+		database := response["checks"].(map[string]interface{})["database"].(map[string]interface{})
+		assert.Equal(t, "failed", database["code"])
+		assert.NotContains(t, database, "error")
+	})
+
+	t.Run("Readyz detecta un worker detenido", func(t *testing.T) {
+		stopped := workers.NewManager(idleWorker{name: "detenido"})
+		stopped.Start()
+		require.NoError(t, stopped.Stop(context.Background()))
+
+		err := stopped.Check(context.Background())
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "detenido")
+		}
+	})
+
+	t.Run("Version con datos de compilación", func(t *testing.T) {
+		previous := buildinfo.Version
+		buildinfo.Version = "1.2.3"
+		defer func() { buildinfo.Version = previous }()
+
+		code, response := probe(t, router, "/version")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "1.2.3", response["version"])
+		assert.Equal(t, runtime.Version(), response["go_version"])
+	})
+}
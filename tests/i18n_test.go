@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/i18n"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestI18nCatalog(t *testing.T) {
+	t.Run("Todos los idiomas tienen las mismas claves", func(t *testing.T) {
+		for _, locale := range i18n.Supported() {
+			assert.ElementsMatch(t, i18n.Keys(i18n.Default), i18n.Keys(locale), "catálogo %s", locale)
+		}
+	})
+
+	t.Run("Negocia el idioma a partir de Accept-Language", func(t *testing.T) {
+		cases := map[string]i18n.Locale{
+			"":                      i18n.Spanish,
+			"en":                    i18n.English,
+			"en-US,en;q=0.9":        i18n.English,
+			"es-MX":                 i18n.Spanish,
+			"fr":                    i18n.Spanish,
+			"fr-FR, en;q=0.5":       i18n.English,
+			"es;q=0.2, en-GB;q=0.8": i18n.English,
+			"no es una cabecera;;":  i18n.Spanish,
+		}
+		for header, expected := range cases {
+			assert.Equal(t, expected, i18n.Negotiate(header), "Accept-Language: %q", header)
+		}
+	})
+
+	t.Run("Una clave sin traducción se devuelve tal cual", func(t *testing.T) {
+		assert.Equal(t, "clave.inexistente", i18n.T(i18n.English, "clave.inexistente"))
+		assert.Equal(t, "Debe tener al menos 3 caracteres", i18n.T(i18n.Spanish, "validation.min.string", "3"))
+	})
+}
+
+func TestLocalizedResponses(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_i18n")
+	const invalidTask = `{"title":"ab"}`
+
+	t.Run("Los errores se traducen y el código no cambia", func(t *testing.T) {
+		es := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, invalidTask, "Accept-Language", "es")
+		en := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, invalidTask, "Accept-Language", "en-US")
+
+		esProblem, enProblem := decodeProblem(t, es), decodeProblem(t, en)
+		assert.Equal(t, esProblem.Code, enProblem.Code)
+		assert.Equal(t, esProblem.Type, enProblem.Type)
+		assert.Equal(t, "Datos inválidos", esProblem.Title)
+		assert.Equal(t, "Invalid data", enProblem.Title)
+		assert.Equal(t, "Must be at least 3 characters long", enProblem.Errors[0].Message)
+		assert.Equal(t, "en", en.Header().Get("Content-Language"))
+		assert.Contains(t, en.Header().Values("Vary"), "Accept-Language")
+	})
+
+	t.Run("Los errores de Task.Validate se traducen", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"   "}`, "Accept-Language", "en")
+		response := decodeProblem(t, w)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "title", response.Errors[0].Field)
+		assert.Equal(t, "This field is required", response.Errors[0].Message)
+	})
+
+	t.Run("Los mensajes de éxito se traducen", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"Tarea TEST en inglés"}`, "Accept-Language", "en")
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "Task created successfully", response["message"])
+	})
+
+	t.Run("Un idioma no soportado usa el idioma por defecto", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, "/api/tasks", "", "", "Accept-Language", "de-DE")
+		assert.Equal(t, "Falta el token de autorización", decodeProblem(t, w).Detail)
+		assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	})
+
+	t.Run("El idioma preferido del usuario tiene prioridad sobre Accept-Language", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPut, "/api/me/preferences", user.Token, `{"language":"en-GB"}`, "Accept-Language", "es")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "Preferences updated", response["message"], "la respuesta ya usa el idioma nuevo")
+		assert.Equal(t, "en", response["preferences"].(map[string]interface{})["language"])
+
+		w = serveRequest(router, http.MethodPost, "/api/tasks", user.Token, invalidTask, "Accept-Language", "es")
+		assert.Equal(t, "Invalid data", decodeProblem(t, w).Title)
+
+		// Sin preferencia se vuelve a usar Accept-Language
+		w = serveRequest(router, http.MethodPut, "/api/me/preferences", user.Token, `{"language":""}`, "Accept-Language", "es")
+		require.Equal(t, http.StatusOK, w.Code)
+		w = serveRequest(router, http.MethodPost, "/api/tasks", user.Token, invalidTask, "Accept-Language", "es")
+		assert.Equal(t, "Datos inválidos", decodeProblem(t, w).Title)
+	})
+
+	t.Run("Rechaza idiomas no soportados", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPut, "/api/me/preferences", user.Token, `{"language":"fr"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []string{"language:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+	})
+}
+
+func TestUserLanguageRepository(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_i18n_db")
+	users := repositories.NewGormRepositories(config.DB).Users
+	ctx := context.Background()
+
+	require.NoError(t, users.UpdateLanguage(ctx, user.ID, "en"))
+	stored, err := users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "en", stored.Language)
+
+	require.NoError(t, users.UpdateTimezone(ctx, user.ID, "Europe/Madrid"))
+	stored, err = users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Madrid", stored.Timezone)
+	assert.Equal(t, "en", stored.Language, "cambiar la zona horaria no cambia el idioma")
+
+	// El idioma guardado se aplica a las peticiones con la sesión del usuario
+	w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"ab"}`)
+	assert.Equal(t, "Invalid data", decodeProblem(t, w).Title)
+}
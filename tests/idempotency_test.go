@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupIdempotencyRouter protege handlers de prueba con el middleware, simulando un usuario autenticado.
+// Las peticiones en curso bloquean la clave durante lease
+func setupIdempotencyRouter(lease time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	repos, _ := newMemoryRepositories()
+	service := services.NewIdempotencyService(repos.Idempotency, time.Hour, lease)
+
+	r := gin.New()
+	r.POST("/operacion", func(c *gin.Context) { c.Set("user_id", uint(1)) }, middleware.Idempotency(service), handler)
+	return r
+}
+
+func postOperation(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/operacion", strings.NewReader(`{}`))
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+	const task = `{"title":"Tarea TEST idempotente"}`
+
+	t.Run("Un reintento devuelve la respuesta guardada sin crear otra tarea", func(t *testing.T) {
+		repos, store := newMemoryRepositories()
+		router := gin.New()
+		routes.SetupRoutes(router, repos)
+		user := createTestUser(t, router, "testuser_idem")
+
+		first := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "crear-1")
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+
+		retry := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "crear-1")
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Contains(t, retry.Header().Get("Content-Type"), "application/json")
+		assert.Len(t, store.tasks, 1)
+
+		// Sin clave cada petición crea una tarea
+		other := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "crear-2")
+		assert.Equal(t, http.StatusCreated, other.Code)
+		assert.Len(t, store.tasks, 2)
+	})
+
+	t.Run("La misma clave con otra petición responde 422", func(t *testing.T) {
+		repos, _ := newMemoryRepositories()
+		router := gin.New()
+		routes.SetupRoutes(router, repos)
+		user := createTestUser(t, router, "testuser_idem")
+
+		serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "clave")
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"Otra tarea TEST"}`, middleware.IdempotencyKeyHeader, "clave")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Las claves son independientes por usuario", func(t *testing.T) {
+		repos, store := newMemoryRepositories()
+		router := gin.New()
+		routes.SetupRoutes(router, repos)
+		alice := createTestUser(t, router, "testuser_idem_alice")
+		bob := createTestUser(t, router, "testuser_idem_bob")
+
+		assert.Equal(t, http.StatusCreated, serveRequest(router, http.MethodPost, "/api/tasks", alice.Token, task, middleware.IdempotencyKeyHeader, "misma").Code)
+		w := serveRequest(router, http.MethodPost, "/api/tasks", bob.Token, task, middleware.IdempotencyKeyHeader, "misma")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Len(t, store.tasks, 2)
+	})
+
+	t.Run("Una clave expirada vuelve a ejecutar la petición", func(t *testing.T) {
+		repos, store := newMemoryRepositories()
+		router := gin.New()
+		routes.SetupRoutes(router, repos, routes.WithIdempotencyTTL(time.Nanosecond))
+		user := createTestUser(t, router, "testuser_idem")
+
+		serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "caduca")
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, task, middleware.IdempotencyKeyHeader, "caduca")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Len(t, store.tasks, 2)
+	})
+
+	t.Run("Una petición concurrente con la misma clave responde 409", func(t *testing.T) {
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		router := setupIdempotencyRouter(time.Minute, func(c *gin.Context) {
+			close(entered)
+			<-release
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- postOperation(router, "concurrente") }()
+		<-entered
+
+		w := postOperation(router, "concurrente")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+		assert.Equal(t, "true", postOperation(router, "concurrente").Header().Get(middleware.IdempotentReplayedHeader))
+	})
+
+	t.Run("Una petición en curso con el plazo vencido deja de bloquear la clave", func(t *testing.T) {
+		// La primera petición nunca termina, como si el proceso se hubiera caído sin liberar la clave
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		var calls atomic.Int32
+		router := setupIdempotencyRouter(time.Nanosecond, func(c *gin.Context) {
+			if calls.Add(1) == 1 {
+				close(entered)
+				<-release
+				return
+			}
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		go postOperation(router, "abandonada")
+		<-entered
+
+		w := postOperation(router, "abandonada")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Un error del servidor libera la clave para reintentar", func(t *testing.T) {
+		var calls atomic.Int32
+		router := setupIdempotencyRouter(time.Minute, func(c *gin.Context) {
+			if calls.Add(1) == 1 {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "caído"})
+				return
+			}
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		assert.Equal(t, http.StatusServiceUnavailable, postOperation(router, "reintento").Code)
+		assert.Equal(t, http.StatusCreated, postOperation(router, "reintento").Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Rechaza claves inválidas", func(t *testing.T) {
+		router := setupIdempotencyRouter(time.Minute, func(c *gin.Context) { c.Status(http.StatusCreated) })
+		assert.Equal(t, http.StatusBadRequest, postOperation(router, "con espacios").Code)
+		assert.Equal(t, http.StatusBadRequest, postOperation(router, strings.Repeat("a", 256)).Code)
+	})
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_idem_db")
+	keys := repositories.NewGormRepositories(config.DB).Idempotency
+	ctx := context.Background()
+
+	t.Run("Detecta claves duplicadas y guarda la respuesta", func(t *testing.T) {
+		record := &models.IdempotencyKey{UserID: user.ID, Key: "db-1", RequestHash: strings.Repeat("a", 64), ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, keys.Create(ctx, record))
+
+		duplicate := &models.IdempotencyKey{UserID: user.ID, Key: "db-1", RequestHash: strings.Repeat("b", 64), ExpiresAt: time.Now().Add(time.Hour)}
+		assert.ErrorIs(t, keys.Create(ctx, duplicate), repositories.ErrDuplicate)
+
+		replaced, err := keys.ReplaceExpired(ctx, duplicate, time.Now())
+		require.NoError(t, err)
+		assert.False(t, replaced, "la clave sigue vigente")
+
+		require.NoError(t, keys.Complete(ctx, record.ID, http.StatusCreated, "application/json", []byte(`{"id":1}`)))
+		stored, err := keys.FindByKey(ctx, user.ID, "db-1")
+		require.NoError(t, err)
+		assert.True(t, stored.Completed())
+		assert.Equal(t, `{"id":1}`, string(stored.ResponseBody))
+
+		deleted, err := keys.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+	})
+
+	t.Run("Reutiliza una petición en curso con el plazo vencido", func(t *testing.T) {
+		lockedUntil := time.Now().Add(-time.Second)
+		record := &models.IdempotencyKey{UserID: user.ID, Key: "db-caida", RequestHash: strings.Repeat("a", 64),
+			ExpiresAt: time.Now().Add(time.Hour), LockedUntil: &lockedUntil}
+		require.NoError(t, keys.Create(ctx, record))
+
+		newLease := time.Now().Add(time.Minute)
+		retry := &models.IdempotencyKey{UserID: user.ID, Key: "db-caida", RequestHash: strings.Repeat("a", 64),
+			ExpiresAt: time.Now().Add(time.Hour), LockedUntil: &newLease}
+		replaced, err := keys.ReplaceExpired(ctx, retry, time.Now())
+		require.NoError(t, err)
+		require.True(t, replaced)
+		assert.Equal(t, record.ID, retry.ID)
+
+		// Con el plazo renovado la clave vuelve a estar bloqueada
+		replaced, err = keys.ReplaceExpired(ctx, retry, time.Now())
+		require.NoError(t, err)
+		assert.False(t, replaced)
+	})
+
+	t.Run("Un reintento de CreateTask no duplica la tarea en la base de datos", func(t *testing.T) {
+		body := `{"title":"Tarea TEST idempotente en MySQL"}`
+		first := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, body, middleware.IdempotencyKeyHeader, "db-crear")
+		require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+		retry := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, body, middleware.IdempotencyKeyHeader, "db-crear")
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+
+		var count int64
+		config.DB.Model(&models.Task{}).Where("title = ?", "Tarea TEST idempotente en MySQL").Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+}
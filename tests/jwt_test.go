@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// writePEMKey guarda una clave en formato PEM en un archivo temporal
+func writePEMKey(t *testing.T, name string, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useJWTConfig carga las claves indicadas y restaura la configuración al terminar
+func useJWTConfig(t *testing.T, cfg config.JWTConfig) {
+	t.Cleanup(func() {
+		if config.App != nil {
+			config.LoadJWTKeys(config.App.JWT)
+		}
+	})
+
+	if cfg.Issuer == "" {
+		cfg.Issuer = "go-task-api"
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = "go-task-api"
+	}
+	if err := config.LoadJWTKeys(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tokenHeader decodifica la cabecera de un token sin validarlo
+func tokenHeader(t *testing.T, tokenString string) map[string]interface{} {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &config.Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.Header
+}
+
+func TestJWTSigning(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaDER, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	rsaPath := writePEMKey(t, "rsa.pem", "PRIVATE KEY", rsaDER)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edPath := writePEMKey(t, "ed25519.pem", "PRIVATE KEY", edDER)
+
+	t.Run("HS256 con JWT_SECRET", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{Secret: "secreto-de-prueba"})
+
+		token, err := config.GenerateToken("testuser_jwt", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "HS256", tokenHeader(t, token)["alg"])
+
+		claims, err := config.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "testuser_jwt", claims.Username)
+		assert.Equal(t, uint(1), claims.SessionID)
+		assert.Equal(t, "go-task-api", claims.Issuer)
+
+		// Las claves HMAC nunca se publican
+		assert.Empty(t, config.PublicJWKS()["keys"])
+	})
+
+	t.Run("Sin claves configuradas", func(t *testing.T) {
+		assert.Error(t, config.LoadJWTKeys(config.JWTConfig{}))
+	})
+
+	t.Run("RS256 con kid", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath})
+
+		token, err := config.GenerateToken("testuser_jwt", 1)
+		assert.NoError(t, err)
+		header := tokenHeader(t, token)
+		assert.Equal(t, "RS256", header["alg"])
+		assert.NotEmpty(t, header["kid"])
+
+		claims, err := config.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "testuser_jwt", claims.Username)
+	})
+
+	t.Run("EdDSA con kid", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: edPath})
+
+		token, err := config.GenerateToken("testuser_jwt", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "EdDSA", tokenHeader(t, token)["alg"])
+
+		_, err = config.ValidateToken(token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rotación de claves", func(t *testing.T) {
+		// Token emitido con la clave anterior (RSA)
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath})
+		oldToken, _ := config.GenerateToken("testuser_jwt", 1)
+
+		// La clave nueva firma; la anterior sigue aceptándose para verificar
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: edPath, VerificationKeyFiles: []string{rsaPath}})
+		_, err := config.ValidateToken(oldToken)
+		assert.NoError(t, err)
+
+		newToken, _ := config.GenerateToken("testuser_jwt", 1)
+		assert.Equal(t, "EdDSA", tokenHeader(t, newToken)["alg"])
+		assert.Len(t, config.PublicJWKS()["keys"], 2)
+
+		// Al retirar la clave anterior sus tokens dejan de ser válidos
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: edPath})
+		_, err = config.ValidateToken(oldToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("Rechazar issuer y audiencia incorrectos", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath, Issuer: "otro-servicio"})
+		token, _ := config.GenerateToken("testuser_jwt", 1)
+
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath})
+		_, err := config.ValidateToken(token)
+		assert.Error(t, err)
+
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath, Audience: "otra-audiencia"})
+		token, _ = config.GenerateToken("testuser_jwt", 1)
+
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath})
+		_, err = config.ValidateToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("Rechazar token antes de nbf", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath})
+		signingToken, _ := config.GenerateToken("testuser_jwt", 1)
+		kid := tokenHeader(t, signingToken)["kid"]
+
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, config.Claims{
+			Username: "testuser_jwt",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "go-task-api",
+				Audience:  jwt.ClaimStrings{"go-task-api"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Hour)),
+				NotBefore: jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		})
+		token.Header["kid"] = kid
+		tokenString, _ := token.SignedString(rsaKey)
+
+		_, err := config.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, jwt.ErrTokenNotValidYet)
+	})
+
+	t.Run("Rechazar cambio de algoritmo", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath, Secret: "secreto-de-prueba"})
+		signingToken, _ := config.GenerateToken("testuser_jwt", 1)
+		kid := tokenHeader(t, signingToken)["kid"]
+
+		// Token HS256 que declara el kid de la clave RSA
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, config.Claims{
+			Username: "testuser_jwt",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "go-task-api",
+				Audience:  jwt.ClaimStrings{"go-task-api"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		token.Header["kid"] = kid
+		tokenString, _ := token.SignedString([]byte("secreto-de-prueba"))
+
+		_, err := config.ValidateToken(tokenString)
+		assert.Error(t, err)
+	})
+
+	t.Run("Endpoint JWKS", func(t *testing.T) {
+		useJWTConfig(t, config.JWTConfig{PrivateKeyFile: rsaPath, Secret: "secreto-de-prueba"})
+		token, _ := config.GenerateToken("testuser_jwt", 1)
+
+		req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+		setupRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if assert.Len(t, response.Keys, 1) {
+			assert.Equal(t, "RSA", response.Keys[0]["kty"])
+			assert.Equal(t, "RS256", response.Keys[0]["alg"])
+			assert.Equal(t, tokenHeader(t, token)["kid"], response.Keys[0]["kid"])
+			assert.NotContains(t, response.Keys[0], "d")
+		}
+	})
+}
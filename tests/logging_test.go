@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/logging"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs instala un logger JSON que escribe en un buffer mientras dure el test
+func captureLogs(t *testing.T, level string) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(config.LogConfig{Level: level, Format: "json"}, &buf))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// accessLogEntries retorna las líneas del log de acceso escritas en buf
+func accessLogEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		if entry["msg"] == "Petición HTTP" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// setupLoggedRouter construye el router en memoria con los middlewares de main
+func setupLoggedRouter(t *testing.T) *gin.Engine {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.AccessLog(), middleware.Recovery())
+	routes.SetupRoutes(r, repos)
+	r.GET("/panic", func(c *gin.Context) { panic("fallo inesperado") })
+	return r
+}
+
+func TestRequestLogging(t *testing.T) {
+	logs := captureLogs(t, "info")
+	router := setupLoggedRouter(t)
+
+	t.Run("Respeta el X-Request-ID recibido y lo devuelve en los errores", func(t *testing.T) {
+		logs.Reset()
+		w, req := makeAuthenticatedRequest("GET", "/api/tasks?secreto=1", "", nil)
+		req.Header.Del("Authorization")
+		req.Header.Set("X-Request-ID", "abc-123")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "abc-123", w.Header().Get("X-Request-ID"))
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "abc-123", response["request_id"])
+
+		entries := accessLogEntries(t, logs)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "abc-123", entries[0]["request_id"])
+		assert.Equal(t, "/api/tasks", entries[0]["route"])
+		assert.Equal(t, "/api/tasks", entries[0]["path"], "la query string no se registra")
+		assert.Equal(t, float64(http.StatusUnauthorized), entries[0]["status"])
+		assert.Equal(t, "INFO", entries[0]["level"])
+		assert.Contains(t, entries[0], "latency_ms")
+	})
+
+	t.Run("Genera un ID si no se recibe o no es válido", func(t *testing.T) {
+		for _, incoming := range []string{"", "id con espacios", strings.Repeat("a", 200)} {
+			w, req := makeAuthenticatedRequest("GET", "/api/tasks", "", nil)
+			req.Header.Set("X-Request-ID", incoming)
+			router.ServeHTTP(w, req)
+
+			generated := w.Header().Get("X-Request-ID")
+			assert.Len(t, generated, 32)
+			assert.NotEqual(t, incoming, generated)
+		}
+	})
+
+	t.Run("Registra el usuario autenticado", func(t *testing.T) {
+		user := createTestUser(t, router, "logged_user")
+		require.NotEmpty(t, user.Token)
+
+		logs.Reset()
+		w, req := makeAuthenticatedRequest("GET", "/api/tasks", user.Token, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		entries := accessLogEntries(t, logs)
+		require.Len(t, entries, 1)
+		assert.Equal(t, float64(user.ID), entries[0]["user_id"])
+		assert.Equal(t, "jwt", entries[0]["auth_method"])
+	})
+
+	t.Run("Un panic responde 500 con el ID de la petición", func(t *testing.T) {
+		logs.Reset()
+		w, req := makeAuthenticatedRequest("GET", "/panic", "", nil)
+		req.Header.Set("X-Request-ID", "panic-1")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "panic-1", response["request_id"])
+
+		assert.Contains(t, logs.String(), `"panic":"fallo inesperado"`)
+		entries := accessLogEntries(t, logs)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ERROR", entries[0]["level"])
+	})
+
+	t.Run("El nivel configurado filtra los mensajes", func(t *testing.T) {
+		quiet := captureLogs(t, "warn")
+		w, req := makeAuthenticatedRequest("GET", "/api/tasks", "", nil)
+		router.ServeHTTP(w, req)
+		assert.Empty(t, accessLogEntries(t, quiet))
+	})
+
+	t.Run("Nivel y formato inválidos", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Database.User, cfg.Database.Name, cfg.JWT.Secret = "root", "tasks_db", "secret"
+		require.NoError(t, cfg.Validate())
+
+		cfg.Log.Level = "verbose"
+		cfg.Log.Format = "xml"
+		err := cfg.Validate()
+		assert.ErrorContains(t, err, "LOG_LEVEL")
+		assert.ErrorContains(t, err, "LOG_FORMAT")
+	})
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/metrics"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/workers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Metrics())
+	routes.SetupRoutes(router, repos)
+	routes.SetupMetricsRoutes(router)
+
+	serve := func(method, url string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Cuenta las peticiones por plantilla de ruta", func(t *testing.T) {
+		byTemplate := metrics.HTTPRequests.WithLabelValues("PUT", "/api/tasks/:id", "401")
+		before := testutil.ToFloat64(byTemplate)
+
+		assert.Equal(t, http.StatusUnauthorized, serve("PUT", "/api/tasks/123"))
+		assert.Equal(t, http.StatusUnauthorized, serve("PUT", "/api/tasks/456"))
+		assert.Equal(t, before+2, testutil.ToFloat64(byTemplate))
+	})
+
+	t.Run("Las rutas desconocidas comparten una sola serie", func(t *testing.T) {
+		unmatched := metrics.HTTPRequests.WithLabelValues("GET", metrics.UnmatchedRoute, "404")
+		other := metrics.HTTPRequests.WithLabelValues("OTHER", metrics.UnmatchedRoute, "404")
+		before, beforeOther := testutil.ToFloat64(unmatched), testutil.ToFloat64(other)
+
+		serve("GET", "/no/existe/1")
+		serve("GET", "/no/existe/2")
+		serve("BREW", "/cafe")
+		assert.Equal(t, before+2, testutil.ToFloat64(unmatched))
+		assert.Equal(t, beforeOther+1, testutil.ToFloat64(other))
+	})
+
+	t.Run("Cuenta los logins correctos y fallidos", func(t *testing.T) {
+		success := metrics.Logins.WithLabelValues("password", "success")
+		failure := metrics.Logins.WithLabelValues("password", "failure")
+		beforeSuccess, beforeFailure := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+		user := createTestUser(t, router, "metrics_user")
+		require.NotEmpty(t, user.Token)
+
+		w, req := makeAuthenticatedRequest("POST", "/api/login", "", map[string]string{
+			"email":    user.Email,
+			"password": "contraseña-incorrecta",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		assert.Equal(t, beforeSuccess+1, testutil.ToFloat64(success))
+		assert.Equal(t, beforeFailure+1, testutil.ToFloat64(failure))
+	})
+
+	t.Run("Publica las tareas por estado y las vencidas", func(t *testing.T) {
+		ctx := context.Background()
+		yesterday := time.Now().Add(-24 * time.Hour)
+		for _, task := range []models.Task{
+			{Title: "Vencida", Status: models.TaskStatusPending, DueDate: &yesterday, UserID: 1},
+			{Title: "Completada tarde", Status: models.TaskStatusCompleted, DueDate: &yesterday, UserID: 1},
+			{Title: "Sin fecha", Status: models.TaskStatusPending, UserID: 2},
+		} {
+			require.NoError(t, repos.Tasks.Create(ctx, &task))
+		}
+
+		worker := workers.NewTaskMetrics(repos.Tasks, time.Hour)
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			worker.Run(runCtx)
+			close(done)
+		}()
+		assert.Eventually(t, func() bool {
+			return testutil.ToFloat64(metrics.TasksOverdue) == 1
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		<-done
+
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.Tasks.WithLabelValues(models.TaskStatusPending)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Tasks.WithLabelValues(models.TaskStatusCompleted)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.Tasks.WithLabelValues(models.TaskStatusInProgress)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.Tasks.WithLabelValues(repositories.OtherTaskStatus)))
+	})
+
+	t.Run("Expone las métricas en formato Prometheus", func(t *testing.T) {
+		// sql.Open no conecta: basta para publicar las estadísticas del pool
+		pool, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/metrics")
+		require.NoError(t, err)
+		defer pool.Close()
+		pool.SetMaxOpenConns(7)
+		require.NoError(t, metrics.RegisterDB("metrics_test", pool))
+		require.NoError(t, metrics.RegisterDB("metrics_test", pool), "registrar de nuevo reemplaza el anterior")
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/metrics", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		body, _ := io.ReadAll(w.Body)
+		text := string(body)
+		assert.Contains(t, text, `taskapi_http_requests_total{method="PUT",route="/api/tasks/:id",status="401"}`)
+		assert.Contains(t, text, `taskapi_http_request_duration_seconds_bucket{method="PUT",route="/api/tasks/:id",status="401",le="0.005"}`)
+		assert.Contains(t, text, `taskapi_logins_total{method="password",result="success"}`)
+		assert.Contains(t, text, `taskapi_tasks{status="pending"}`)
+		assert.Contains(t, text, `taskapi_tasks_overdue`)
+		assert.Contains(t, text, `go_sql_max_open_connections{db_name="metrics_test"} 7`)
+		assert.NotContains(t, text, "/api/tasks/123", "las URLs concretas no son etiquetas")
+	})
+}
+
+func TestTaskCountsFromDatabase(t *testing.T) {
+	setupTestDB()
+	defer cleanupTestData()
+	ctx := context.Background()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_counts")
+	require.NotZero(t, user.ID)
+
+	tasks := repositories.NewGormRepositories(config.DB).Tasks
+	before, err := tasks.CountAll(ctx, time.Now())
+	require.NoError(t, err)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	for _, task := range []models.Task{
+		{Title: "TEST vencida", Status: models.TaskStatusInProgress, DueDate: &yesterday, UserID: user.ID},
+		{Title: "TEST completada", Status: models.TaskStatusCompleted, DueDate: &yesterday, UserID: user.ID},
+		{Title: "TEST flujo propio", Status: "shipped", UserID: user.ID},
+	} {
+		require.NoError(t, tasks.Create(ctx, &task))
+	}
+
+	after, err := tasks.CountAll(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, before.ByStatus[models.TaskStatusInProgress]+1, after.ByStatus[models.TaskStatusInProgress])
+	assert.Equal(t, before.ByStatus[models.TaskStatusCompleted]+1, after.ByStatus[models.TaskStatusCompleted])
+	assert.Equal(t, before.Overdue+1, after.Overdue)
+	// Los estados de los flujos propios no crean series nuevas
+	assert.Equal(t, before.ByStatus[repositories.OtherTaskStatus]+1, after.ByStatus[repositories.OtherTaskStatus])
+	assert.NotContains(t, after.ByStatus, "shipped")
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openEmptyDatabase crea una base de datos vacía en el mismo servidor que la de pruebas
+func openEmptyDatabase(t *testing.T, name string) *gorm.DB {
+	cfg := config.App.Database
+	server := fmt.Sprintf("%s:%s@tcp(%s:%s)/", cfg.User, cfg.Password, cfg.Host, cfg.Port)
+	quiet := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+
+	admin, err := gorm.Open(mysql.Open(server+"?parseTime=True"), quiet)
+	require.NoError(t, err)
+	require.NoError(t, admin.Exec("DROP DATABASE IF EXISTS "+name).Error)
+	require.NoError(t, admin.Exec("CREATE DATABASE "+name).Error)
+
+	db, err := gorm.Open(mysql.Open(server+name+"?charset=utf8mb4&parseTime=True&loc=Local"), quiet)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP DATABASE IF EXISTS " + name)
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestMigrations(t *testing.T) {
+	setupTestDB()
+	ctx := context.Background()
+
+	t.Run("Aplicar y revertir las migraciones incluidas", func(t *testing.T) {
+		db := openEmptyDatabase(t, "migrations_embedded")
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, migrator.Check(ctx), migrations.ErrSchemaBehind)
+
+		applied, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.NotEmpty(t, applied)
+		assert.NoError(t, migrator.Check(ctx))
+		for _, table := range []string{"users", "tasks", "api_keys", "user_identities", "sessions", "idempotency_keys", "workflow_statuses", "workflow_transitions", "task_status_transitions"} {
+			assert.True(t, db.Migrator().HasTable(table), "falta la tabla %s", table)
+		}
+
+		// Volver a ejecutar up no aplica nada
+		again, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+
+		reverted, err := migrator.Down(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, applied[len(applied)-1].Version, reverted[0].Version)
+		assert.ErrorIs(t, migrator.Check(ctx), migrations.ErrSchemaBehind)
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.False(t, statuses[len(statuses)-1].Applied)
+		assert.True(t, statuses[0].Applied)
+		assert.NotNil(t, statuses[0].AppliedAt)
+
+		_, err = migrator.Down(ctx, len(applied))
+		require.NoError(t, err)
+		assert.False(t, db.Migrator().HasTable("users"))
+	})
+
+	t.Run("Una migración fallida no se registra", func(t *testing.T) {
+		db := openEmptyDatabase(t, "migrations_failing")
+		migrator, err := migrations.NewFromFS(db, fstest.MapFS{
+			"0001_create_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id bigint NOT NULL, PRIMARY KEY (id));\nINSERT INTO notes VALUES (1);")},
+			"0001_create_notes.down.sql": {Data: []byte("DROP TABLE notes;")},
+			"0002_broken.up.sql":         {Data: []byte("ALTER TABLE missing_table ADD COLUMN x int;")},
+			"0002_broken.down.sql":       {Data: []byte("SELECT 1;")},
+		})
+		require.NoError(t, err)
+
+		applied, err := migrator.Up(ctx)
+		assert.Error(t, err)
+		require.Len(t, applied, 1)
+		assert.Equal(t, int64(1), applied[0].Version)
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.True(t, statuses[0].Applied)
+		assert.False(t, statuses[1].Applied)
+
+		var count int64
+		db.Table("notes").Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Rechazar archivos de migración inválidos", func(t *testing.T) {
+		_, err := migrations.Load(fstest.MapFS{
+			"0001_sin_down.up.sql": {Data: []byte("SELECT 1;")},
+		})
+		assert.Error(t, err)
+
+		_, err = migrations.Load(fstest.MapFS{
+			"cambios.sql": {Data: []byte("SELECT 1;")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Crear una migración con la siguiente versión", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_previa.up.sql"), []byte("SELECT 1;"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_previa.down.sql"), []byte("SELECT 1;"), 0644))
+
+		up, down, err := migrations.Create(dir, "Agregar prioridad")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "0008_agregar_prioridad.up.sql"), up)
+		assert.FileExists(t, up)
+		assert.FileExists(t, down)
+
+		loaded, err := migrations.Load(os.DirFS(dir))
+		require.NoError(t, err)
+		assert.Len(t, loaded, 2)
+	})
+}
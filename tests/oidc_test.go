@@ -0,0 +1,328 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIdP es un proveedor OIDC mínimo para tests: discovery, JWKS y token endpoint con PKCE
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+// mockAuthorization representa un código emitido por el IdP simulado
+type mockAuthorization struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &mockIdP{key: key, codes: make(map[string]mockAuthorization)}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "mock-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		idp.mu.Lock()
+		authorization, ok := idp.codes[r.Form.Get("code")]
+		delete(idp.codes, r.Form.Get("code"))
+		idp.mu.Unlock()
+
+		// Verificar PKCE: S256(code_verifier) debe coincidir con el code_challenge
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, authorization.claims)
+		token.Header["kid"] = "mock-key"
+		idToken, _ := token.SignedString(key)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "mock-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize simula que el usuario se autenticó en el IdP y retorna el código emitido
+func (idp *mockIdP) authorize(authURL *url.URL, subject string, claims jwt.MapClaims) string {
+	query := authURL.Query()
+	now := time.Now()
+
+	claims["iss"] = idp.server.URL
+	claims["sub"] = subject
+	claims["aud"] = query.Get("client_id")
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(time.Hour).Unix()
+	if _, ok := claims["nonce"]; !ok {
+		claims["nonce"] = query.Get("nonce")
+	}
+
+	code := "code-" + subject
+	idp.mu.Lock()
+	idp.codes[code] = mockAuthorization{challenge: query.Get("code_challenge"), claims: claims}
+	idp.mu.Unlock()
+	return code
+}
+
+// setupOIDC configura la app para usar el IdP simulado
+func setupOIDC(t *testing.T, idp *mockIdP) {
+	t.Cleanup(func() { config.OIDC = nil })
+
+	err := config.InitOIDC(context.Background(), config.OIDCConfig{
+		IssuerURL:    idp.server.URL,
+		ClientID:     "task-api",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/auth/oidc/callback",
+		AllowSignup:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startOIDCLogin llama a /api/auth/oidc/login y retorna la URL del IdP y la cookie de state
+func startOIDCLogin(t *testing.T, router *gin.Engine) (*url.URL, *http.Cookie) {
+	req, _ := http.NewRequest("GET", "/api/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	authURL, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("no se recibió la cookie de state")
+	}
+	return authURL, cookies[0]
+}
+
+// finishOIDCLogin llama al callback con el código emitido por el IdP
+func finishOIDCLogin(router *gin.Engine, state string, code string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/api/auth/oidc/callback?state="+url.QueryEscape(state)+"&code="+url.QueryEscape(code), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOIDCLogin(t *testing.T) {
+	setupTestDB()
+	defer cleanupTestData()
+
+	t.Run("Login sin OIDC configurado", func(t *testing.T) {
+		router := setupRouter()
+
+		req, _ := http.NewRequest("GET", "/api/auth/oidc/login", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// El controlador recibe el proveedor al construir el router
+	idp := newMockIdP(t)
+	setupOIDC(t, idp)
+	router := setupRouter()
+
+	t.Run("Redirección al IdP con PKCE, state y nonce", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		query := authURL.Query()
+
+		assert.Equal(t, idp.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+		assert.Equal(t, "code", query.Get("response_type"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.NotEmpty(t, query.Get("code_challenge"))
+		assert.NotEmpty(t, query.Get("nonce"))
+		assert.Equal(t, query.Get("state"), cookie.Value)
+		assert.True(t, cookie.HttpOnly)
+	})
+
+	t.Run("Provisionar usuario nuevo", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-new", jwt.MapClaims{
+			"email":              "testuser_oidc_new@test.com",
+			"email_verified":     true,
+			"preferred_username": "testuser_oidc_new",
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NotEmpty(t, response["token"])
+		user := response["user"].(map[string]interface{})
+		assert.Equal(t, "testuser_oidc_new", user["username"])
+
+		// El token emitido sirve para la API
+		w, req := makeAuthenticatedRequest("GET", "/api/tasks", response["token"].(string), nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Un segundo login con la misma identidad reutiliza el usuario
+		authURL, cookie = startOIDCLogin(t, router)
+		code = idp.authorize(authURL, "subject-new", jwt.MapClaims{
+			"email":              "testuser_oidc_new@test.com",
+			"email_verified":     true,
+			"preferred_username": "otro_nombre",
+		})
+		w = finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, user["id"], response["user"].(map[string]interface{})["id"])
+	})
+
+	t.Run("Vincular usuario existente por email verificado", func(t *testing.T) {
+		existing := createTestUser(t, router, "testuser_oidc_link")
+
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-link", jwt.MapClaims{
+			"email":          existing.Email,
+			"email_verified": true,
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		user := response["user"].(map[string]interface{})
+		assert.Equal(t, float64(existing.ID), user["id"])
+	})
+
+	t.Run("Un email sin verificar de otro usuario responde 409", func(t *testing.T) {
+		existing := createTestUser(t, router, "testuser_oidc_unverified")
+
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-unverified", jwt.MapClaims{
+			"email":          existing.Email,
+			"email_verified": false,
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		assert.Equal(t, "email_taken", decodeProblem(t, w).Code)
+	})
+
+	t.Run("No crear usuarios si el registro está deshabilitado", func(t *testing.T) {
+		config.OIDC.AllowSignup = false
+		defer func() { config.OIDC.AllowSignup = true }()
+
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-nosignup", jwt.MapClaims{
+			"email":          "testuser_oidc_nosignup@test.com",
+			"email_verified": true,
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("State inválido", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-state", jwt.MapClaims{"email": "testuser_oidc_state@test.com"})
+
+		w := finishOIDCLogin(router, "state-falso", code, cookie)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Sin la cookie del navegador que inició el login
+		w = finishOIDCLogin(router, authURL.Query().Get("state"), code, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("State no reutilizable", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		claims := jwt.MapClaims{"email": "testuser_oidc_replay@test.com", "preferred_username": "testuser_oidc_replay"}
+		code := idp.authorize(authURL, "subject-replay", claims)
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Nonce incorrecto", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-nonce", jwt.MapClaims{
+			"email": "testuser_oidc_nonce@test.com",
+			"nonce": "nonce-falso",
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Error devuelto por el IdP", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/auth/oidc/callback?error=access_denied", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
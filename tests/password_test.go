@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// registerUser intenta registrar un usuario y retorna la respuesta
+func registerUser(router *gin.Engine, username, email, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{
+		"username": username,
+		"email":    email,
+		"password": password,
+	})
+	req, _ := http.NewRequest("POST", "/api/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// loginUser intenta iniciar sesión y retorna el código de respuesta
+func loginUser(router *gin.Engine, email, password string) int {
+	body, _ := json.Marshal(map[string]string{"email": email, "password": password})
+	req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+// storedPasswordHash lee el hash guardado de un usuario
+func storedPasswordHash(email string) string {
+	var user models.User
+	config.DB.Where("email = ?", email).First(&user)
+	return user.Password
+}
+
+// usePasswordConfig modifica la configuración de contraseñas y la restaura al terminar
+func usePasswordConfig(t *testing.T, change func(cfg *config.PasswordConfig)) {
+	t.Cleanup(func() { config.LoadPasswordSettings(config.App.Password) })
+
+	cfg := config.App.Password
+	change(&cfg)
+	if err := config.LoadPasswordSettings(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	setupTestDB()
+	router := setupRouter()
+	defer cleanupTestData()
+
+	t.Run("Rechazar contraseñas que no cumplen la política", func(t *testing.T) {
+		cases := map[string]string{
+			"Muy corta":            "Ab1-",
+			"Muy larga":            strings.Repeat("a1", 65),
+			"Común":                "password123",
+			"Común con mayúsculas": "QWERTY123",
+			"Contiene el usuario":  "testuser_policy-2024",
+			"Contiene el email":    "xx-testuser_policy_mail-99",
+		}
+
+		for name, password := range cases {
+			w := registerUser(router, "testuser_policy", "testuser_policy_mail@test.com", password)
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NotEmpty(t, response["errors"], name)
+		}
+	})
+
+	t.Run("Política por defecto compatible con la anterior", func(t *testing.T) {
+		w := registerUser(router, "testuser_policy_default", "testuser_policy_default@test.com", "tareas")
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+
+	t.Run("Política configurable", func(t *testing.T) {
+		usePasswordConfig(t, func(cfg *config.PasswordConfig) {
+			cfg.MinLength = 12
+			cfg.RequireUpper = true
+			cfg.RequireDigit = true
+			cfg.RequireSymbol = true
+		})
+
+		w := registerUser(router, "testuser_policy_cfg", "testuser_policy_cfg@test.com", "tareas-prueba")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response["errors"], 2)
+
+		w = registerUser(router, "testuser_policy_cfg", "testuser_policy_cfg@test.com", "Tareas-Largas-2024")
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Límite de 72 bytes solo con bcrypt", func(t *testing.T) {
+		// 60 caracteres que ocupan 90 bytes: cada á ocupa dos
+		password := strings.Repeat("á1", 30)
+
+		w := registerUser(router, "testuser_policy_argon", "testuser_policy_argon@test.com", password)
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		usePasswordConfig(t, func(cfg *config.PasswordConfig) { cfg.HashAlgorithm = config.PasswordHashBcrypt })
+		w = registerUser(router, "testuser_policy_bcrypt", "testuser_policy_bcrypt@test.com", password)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		response := decodeProblem(t, w)
+		if assert.Len(t, response.Errors, 1) {
+			assert.Contains(t, response.Errors[0].Message, "72 bytes")
+		}
+	})
+
+	t.Run("Lista de contraseñas bloqueadas adicional", func(t *testing.T) {
+		path := t.TempDir() + "/blocklist.txt"
+		os.WriteFile(path, []byte("# filtradas\nNombre-Empresa-2024\n"), 0600)
+		usePasswordConfig(t, func(cfg *config.PasswordConfig) { cfg.BlocklistFile = path })
+
+		w := registerUser(router, "testuser_policy_list", "testuser_policy_list@test.com", "nombre-empresa-2024")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPasswordHashing(t *testing.T) {
+	setupTestDB()
+	router := setupRouter()
+	defer cleanupTestData()
+
+	t.Run("Usuarios nuevos usan argon2id", func(t *testing.T) {
+		user := createTestUser(t, router, "testuser_argon2")
+
+		assert.True(t, strings.HasPrefix(storedPasswordHash(user.Email), "$argon2id$v=19$"))
+		assert.Equal(t, http.StatusOK, loginUser(router, user.Email, user.Password))
+		assert.Equal(t, http.StatusUnauthorized, loginUser(router, user.Email, "Otra-Clave-2024"))
+	})
+
+	t.Run("Rehash de bcrypt a argon2id al iniciar sesión", func(t *testing.T) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("Clave-Legacy-2019"), bcrypt.MinCost)
+		legacy := models.User{Username: "testuser_legacy", Email: "testuser_legacy@test.com", Password: string(hash)}
+		config.DB.Create(&legacy)
+
+		// Un intento fallido no modifica el hash
+		assert.Equal(t, http.StatusUnauthorized, loginUser(router, legacy.Email, "incorrecta"))
+		assert.Equal(t, string(hash), storedPasswordHash(legacy.Email))
+
+		assert.Equal(t, http.StatusOK, loginUser(router, legacy.Email, "Clave-Legacy-2019"))
+		assert.True(t, strings.HasPrefix(storedPasswordHash(legacy.Email), "$argon2id$"))
+
+		// El nuevo hash sigue funcionando
+		assert.Equal(t, http.StatusOK, loginUser(router, legacy.Email, "Clave-Legacy-2019"))
+	})
+
+	t.Run("Rehash cuando cambia el coste", func(t *testing.T) {
+		usePasswordConfig(t, func(cfg *config.PasswordConfig) {
+			cfg.HashAlgorithm = config.PasswordHashBcrypt
+			cfg.BcryptCost = 5
+		})
+
+		hash, _ := bcrypt.GenerateFromPassword([]byte("Clave-Coste-2019"), bcrypt.MinCost)
+		legacy := models.User{Username: "testuser_cost", Email: "testuser_cost@test.com", Password: string(hash)}
+		config.DB.Create(&legacy)
+
+		assert.Equal(t, http.StatusOK, loginUser(router, legacy.Email, "Clave-Coste-2019"))
+
+		cost, err := bcrypt.Cost([]byte(storedPasswordHash(legacy.Email)))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, cost)
+	})
+
+	t.Run("Rehash cuando cambian los parámetros de argon2id", func(t *testing.T) {
+		user := createTestUser(t, router, "testuser_argon2_params")
+		usePasswordConfig(t, func(cfg *config.PasswordConfig) { cfg.Argon2Iterations = 2 })
+
+		assert.Equal(t, http.StatusOK, loginUser(router, user.Email, user.Password))
+		assert.Contains(t, storedPasswordHash(user.Email), ",t=2,")
+	})
+}
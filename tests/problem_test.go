@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/problem"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// problemResponse es el cuerpo de un error problem+json
+type problemResponse struct {
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Status    int                  `json:"status"`
+	Detail    string               `json:"detail"`
+	Instance  string               `json:"instance"`
+	Code      string               `json:"code"`
+	RequestID string               `json:"request_id"`
+	Errors    []problem.FieldError `json:"errors"`
+}
+
+// decodeProblem comprueba el tipo de contenido y decodifica el error
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) problemResponse {
+	t.Helper()
+	assert.Equal(t, problem.MediaType, w.Header().Get("Content-Type"))
+	var response problemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response
+}
+
+func TestProblemDetails(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_problem")
+
+	t.Run("Los errores incluyen tipo, código, ruta e ID de la petición", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, "/api/tasks", "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		response := decodeProblem(t, w)
+		assert.Equal(t, "unauthenticated", response.Code)
+		assert.Equal(t, "urn:task-api:problem:unauthenticated", response.Type)
+		assert.Equal(t, http.StatusUnauthorized, response.Status)
+		assert.NotEmpty(t, response.Title)
+		assert.Equal(t, "/api/tasks", response.Instance)
+		assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), response.RequestID)
+	})
+
+	t.Run("Los errores de binding se devuelven por campo", func(t *testing.T) {
+		body := fmt.Sprintf(`{"title":"ab","description":%q}`, strings.Repeat("a", 1001))
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		response := decodeProblem(t, w)
+		assert.Equal(t, "validation_failed", response.Code)
+		assert.ElementsMatch(t, []string{"title:min", "description:max"}, fieldCodes(response.Errors))
+		assert.NotContains(t, w.Body.String(), "TaskCreateRequest", "no se exponen los mensajes del validador")
+	})
+
+	t.Run("Un tipo incorrecto se asocia al campo", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":123}`)
+		response := decodeProblem(t, w)
+		assert.Equal(t, "validation_failed", response.Code)
+		assert.Equal(t, []string{"title:type"}, fieldCodes(response.Errors))
+	})
+
+	t.Run("Un JSON mal formado responde invalid_body", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_body", decodeProblem(t, w).Code)
+	})
+
+	t.Run("Task.Validate informa de todos los campos inválidos", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"   ","due_date":"2000-01-01T00:00:00Z"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		response := decodeProblem(t, w)
+		assert.Equal(t, "validation_failed", response.Code)
+		assert.ElementsMatch(t, []string{"title:required", "due_date:past"}, fieldCodes(response.Errors))
+	})
+
+	t.Run("Un nombre de usuario repetido responde 409", func(t *testing.T) {
+		w := registerUser(router, "testuser_problem", "otro_email@test.com", "Tareas-Prueba-2024")
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		response := decodeProblem(t, w)
+		assert.Equal(t, "username_taken", response.Code)
+		assert.Equal(t, []string{"username:taken"}, fieldCodes(response.Errors))
+	})
+
+	t.Run("Las rutas inexistentes usan el mismo formato", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, "/api/no-existe", user.Token, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "route_not_found", decodeProblem(t, w).Code)
+	})
+}
+
+// fieldCodes resume los errores de campo como "campo:código"
+func fieldCodes(errs []problem.FieldError) []string {
+	codes := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		codes[i] = fieldErr.Field + ":" + fieldErr.Code
+	}
+	return codes
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/ratelimit"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLimitedRouter construye el router en memoria con las cuotas dadas, como en main
+func setupLimitedRouter(t *testing.T, limits config.RateLimitConfig) *gin.Engine {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	routes.SetupRoutes(r, repos, routes.WithRateLimits(ratelimit.NewMemoryStore(), limits))
+	return r
+}
+
+// loginFrom hace login desde la IP dada
+func loginFrom(router *gin.Engine, ip string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"email":"nadie@test.com","password":"Incorrecta-2024"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":40000"
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Permite una ráfaga del tamaño de la cuota y rechaza la siguiente", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 3, Period: time.Minute}
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			result, err := store.Take(ctx, "cliente", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, remaining, result.Remaining)
+		}
+
+		result, err := store.Take(ctx, "cliente", limit)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		// Un token se recarga cada 20s
+		assert.InDelta(t, 20*time.Second, result.RetryAfter, float64(time.Second))
+		assert.InDelta(t, time.Minute, result.Reset, float64(time.Second))
+
+		other, err := store.Take(ctx, "otro-cliente", limit)
+		require.NoError(t, err)
+		assert.True(t, other.Allowed, "cada clave tiene su propio bucket")
+	})
+
+	t.Run("Los tokens se recargan con el tiempo", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 2, Period: 100 * time.Millisecond}
+
+		store.Take(ctx, "cliente", limit)
+		store.Take(ctx, "cliente", limit)
+		result, _ := store.Take(ctx, "cliente", limit)
+		require.False(t, result.Allowed)
+
+		time.Sleep(60 * time.Millisecond)
+		result, err := store.Take(ctx, "cliente", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestRateLimiting(t *testing.T) {
+	limits := config.RateLimitConfig{
+		Enabled:      true,
+		AuthRequests: 3,
+		AuthPeriod:   config.Duration(time.Hour),
+		APIRequests:  2,
+		APIPeriod:    config.Duration(time.Hour),
+	}
+
+	t.Run("El login se limita por IP con 429 y Retry-After", func(t *testing.T) {
+		router := setupLimitedRouter(t, limits)
+
+		for i := 0; i < 3; i++ {
+			w := loginFrom(router, "203.0.113.10", nil)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, "3;w=3600", w.Header().Get("RateLimit-Policy"))
+		}
+
+		w := loginFrom(router, "203.0.113.10", nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1200", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3600", w.Header().Get("RateLimit-Reset"))
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(1200), response["retry_after"])
+		assert.Equal(t, "rate_limited", response["code"])
+
+		// Otra IP tiene su propia cuota
+		assert.Equal(t, http.StatusUnauthorized, loginFrom(router, "203.0.113.20", nil).Code)
+	})
+
+	t.Run("X-Forwarded-For de un cliente no evita el límite", func(t *testing.T) {
+		router := setupLimitedRouter(t, limits)
+
+		for i := 0; i < 3; i++ {
+			loginFrom(router, "203.0.113.10", map[string]string{"X-Forwarded-For": "198.51.100.1"})
+		}
+		w := loginFrom(router, "203.0.113.10", map[string]string{"X-Forwarded-For": "198.51.100.99"})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("Las rutas protegidas se limitan por usuario", func(t *testing.T) {
+		router := setupLimitedRouter(t, config.RateLimitConfig{
+			Enabled:      true,
+			AuthRequests: 10,
+			AuthPeriod:   config.Duration(time.Hour),
+			APIRequests:  2,
+			APIPeriod:    config.Duration(time.Hour),
+		})
+		alice := createTestUser(t, router, "testuser_limit_alice")
+		bob := createTestUser(t, router, "testuser_limit_bob")
+
+		for i := 0; i < 2; i++ {
+			w, req := makeAuthenticatedRequest(http.MethodGet, "/api/tasks", alice.Token, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		w, req := makeAuthenticatedRequest(http.MethodGet, "/api/tasks", alice.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+
+		// Bob comparte IP con Alice pero no su cuota
+		w, req = makeAuthenticatedRequest(http.MethodGet, "/api/tasks", bob.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("Sin cuotas configuradas no se limita", func(t *testing.T) {
+		router := setupLimitedRouter(t, config.RateLimitConfig{Enabled: false})
+
+		for i := 0; i < 20; i++ {
+			w := loginFrom(router, "203.0.113.10", nil)
+			require.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+}
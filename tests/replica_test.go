@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// Nada escucha en el puerto 1: una réplica caída
+const deadReplicaDSN = "user:pass@tcp(127.0.0.1:1)/taskdb?timeout=1s"
+
+// fixedReplica es un ReplicaSource que siempre ofrece la misma réplica
+type fixedReplica struct {
+	db        *gorm.DB
+	unhealthy bool
+}
+
+func (r *fixedReplica) Replica() *gorm.DB {
+	if r.unhealthy {
+		return nil
+	}
+	return r.db
+}
+
+func (r *fixedReplica) MarkUnhealthy(db *gorm.DB) {
+	r.unhealthy = true
+}
+
+// replicaTitles retorna los títulos de una lista de tareas
+func replicaTitles(tasks []models.Task) []string {
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return titles
+}
+
+func TestReadReplicas(t *testing.T) {
+	setupTestDB()
+	defer cleanupTestData()
+	ctx := context.Background()
+
+	// La "réplica" es otra base del mismo servidor, con datos distintos para saber de dónde se lee
+	replicaDB := openEmptyDatabase(t, "replica_reads")
+	migrator, err := migrations.New(replicaDB)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	cfg := config.App.Database
+	replicaDSN := fmt.Sprintf("%s:%s@tcp(%s:%s)/replica_reads", cfg.User, cfg.Password, cfg.Host, cfg.Port)
+	cfg.Replicas = []string{replicaDSN, deadReplicaDSN}
+	replicas, err := config.ConnectReplicas(cfg)
+	require.NoError(t, err)
+	defer replicas.Close()
+
+	repos := repositories.NewGormRepositoriesWithReplicas(config.DB, replicas)
+	router := gin.New()
+	routes.SetupRoutes(router, repos)
+
+	user := createTestUser(t, router, "testuser_replica")
+	require.NotZero(t, user.ID)
+	require.NoError(t, replicaDB.Exec(
+		"INSERT INTO users (id, username, email, password) VALUES (?, ?, ?, '')", user.ID, user.Username, user.Email).Error)
+	require.NoError(t, replicaDB.Exec(
+		"INSERT INTO tasks (title, status, user_id, created_at) VALUES ('TEST desde la réplica', 'pending', ?, NOW())", user.ID).Error)
+
+	t.Run("Solo se usan las réplicas que responden", func(t *testing.T) {
+		err := replicas.CheckHealth(ctx)
+		assert.ErrorContains(t, err, "127.0.0.1:1")
+
+		for i := 0; i < 4; i++ {
+			assert.NotNil(t, replicas.Replica())
+		}
+	})
+
+	t.Run("La lista de tareas se lee de la réplica", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("GET", "/api/tasks", user.Token, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Tasks []models.Task `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"TEST desde la réplica"}, replicaTitles(response.Tasks))
+	})
+
+	t.Run("Después de escribir, la misma petición lee del primario", func(t *testing.T) {
+		requestCtx := repositories.TrackWrites(ctx)
+
+		before, err := repos.Tasks.FindByUser(requestCtx, user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST desde la réplica"}, replicaTitles(before))
+
+		task := models.Task{Title: "TEST en el primario", Status: models.TaskStatusPending, UserID: user.ID}
+		require.NoError(t, repos.Tasks.Create(requestCtx, &task))
+
+		after, err := repos.Tasks.FindByUser(requestCtx, user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST en el primario"}, replicaTitles(after))
+
+		// Otra petición sin escrituras vuelve a leer de la réplica
+		other, err := repos.Tasks.FindByUser(repositories.TrackWrites(ctx), user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST desde la réplica"}, replicaTitles(other))
+	})
+
+	t.Run("Sin réplicas sanas se lee del primario", func(t *testing.T) {
+		down := cfg
+		down.Replicas = []string{deadReplicaDSN}
+		deadReplicas, err := config.ConnectReplicas(down)
+		require.NoError(t, err)
+		defer deadReplicas.Close()
+		assert.Nil(t, deadReplicas.Replica())
+
+		tasks, err := repositories.NewGormRepositoriesWithReplicas(config.DB, deadReplicas).Tasks.FindByUser(ctx, user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST en el primario"}, replicaTitles(tasks))
+	})
+
+	t.Run("Una réplica que falla al conectar se retira y se repite en el primario", func(t *testing.T) {
+		deadDB, err := gorm.Open(mysql.New(mysql.Config{
+			DSN:                       deadReplicaDSN,
+			SkipInitializeWithVersion: true,
+		}), &gorm.Config{DisableAutomaticPing: true})
+		require.NoError(t, err)
+		source := &fixedReplica{db: deadDB}
+
+		tasks, err := repositories.NewGormRepositoriesWithReplicas(config.DB, source).Tasks.FindByUser(ctx, user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TEST en el primario"}, replicaTitles(tasks))
+		assert.True(t, source.unhealthy)
+	})
+
+	t.Run("Los DSN de las réplicas no se muestran en la configuración", func(t *testing.T) {
+		shown := config.Default()
+		shown.Database.Replicas = []string{"reader:s3cr3t@tcp(replica:3306)/tasks_db"}
+		assert.NotContains(t, shown.String(), "s3cr3t")
+		assert.Equal(t, "reader:s3cr3t@tcp(replica:3306)/tasks_db", shown.Database.Replicas[0])
+	})
+}
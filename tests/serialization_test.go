@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-task-manager-mvc/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// containsPasswordField busca recursivamente una clave "password" en un JSON decodificado
+func containsPasswordField(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if strings.Contains(strings.ToLower(key), "password") {
+				return true
+			}
+			if containsPasswordField(nested) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if containsPasswordField(nested) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assertNoPasswordInResponse verifica que la respuesta no exponga contraseñas ni hashes
+func assertNoPasswordInResponse(t *testing.T, w *httptest.ResponseRecorder, endpoint string) {
+	var response interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s devolvió un JSON inválido: %v", endpoint, err)
+	}
+
+	assert.False(t, containsPasswordField(response), "%s expone un campo password: %s", endpoint, w.Body.String())
+	for _, prefix := range []string{"$argon2id$", "$2a$", "$2b$"} {
+		assert.NotContains(t, w.Body.String(), prefix, "%s expone un hash de contraseña", endpoint)
+	}
+}
+
+func TestUserSerialization(t *testing.T) {
+	t.Run("User nunca serializa la contraseña", func(t *testing.T) {
+		user := models.User{
+			Username: "serializado",
+			Email:    "serializado@test.com",
+			Password: "$2a$10$hashsecreto",
+			Tasks:    []models.Task{{Title: "TEST: relación"}},
+		}
+
+		for _, value := range []interface{}{user, &user, []models.User{user}} {
+			data, err := json.Marshal(value)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(data), "password")
+			assert.NotContains(t, string(data), "hashsecreto")
+			assert.NotContains(t, string(data), "tasks")
+		}
+	})
+}
+
+func TestNoEndpointExposesPassword(t *testing.T) {
+	setupTestDB()
+	idp := newMockIdP(t)
+	setupOIDC(t, idp)
+	router := setupRouter()
+	defer cleanupTestData()
+
+	t.Run("Registro no expone la contraseña", func(t *testing.T) {
+		userData := map[string]string{
+			"username": "testuser_serialization",
+			"email":    "testuser_serialization@test.com",
+			"password": "Tareas-Prueba-2024",
+		}
+
+		body, _ := json.Marshal(userData)
+		req, _ := http.NewRequest("POST", "/api/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assertNoPasswordInResponse(t, w, "POST /api/register")
+	})
+
+	t.Run("Login no expone la contraseña", func(t *testing.T) {
+		loginData := map[string]string{
+			"email":    "testuser_serialization@test.com",
+			"password": "Tareas-Prueba-2024",
+		}
+
+		body, _ := json.Marshal(loginData)
+		req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertNoPasswordInResponse(t, w, "POST /api/login")
+	})
+
+	t.Run("Endpoints con datos del usuario no exponen la contraseña", func(t *testing.T) {
+		testUser := createTestUser(t, router, "testuser_serialization_tasks")
+		createTestAPIKey(t, testUser.Token, "TEST: Serialización", []string{"tasks:read"})
+
+		w, req := makeAuthenticatedRequest("POST", "/api/tasks", testUser.Token, map[string]interface{}{
+			"title": "TEST: Tarea serialización",
+		})
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assertNoPasswordInResponse(t, w, "POST /api/tasks")
+
+		var createResponse map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &createResponse)
+		taskID := int(createResponse["task"].(map[string]interface{})["id"].(float64))
+
+		workflow := map[string]interface{}{"statuses": []map[string]interface{}{
+			{"key": "pending"}, {"key": "in_progress"}, {"key": "completed", "done": true},
+		}}
+
+		requests := []struct {
+			method string
+			url    string
+			body   interface{}
+		}{
+			{"GET", "/api/tasks", nil},
+			{"GET", "/api/task-statuses", nil},
+			{"PUT", fmt.Sprintf("/api/tasks/%d", taskID), map[string]interface{}{"status": "en progreso"}},
+			{"POST", fmt.Sprintf("/api/tasks/%d/move", taskID), map[string]interface{}{"status": "completed", "position": 0}},
+			{"GET", fmt.Sprintf("/api/tasks/%d/transitions", taskID), nil},
+			{"GET", "/api/board", nil},
+			{"GET", "/api/stats", nil},
+			{"GET", "/api/me/preferences", nil},
+			{"PUT", "/api/me/preferences", map[string]interface{}{"language": "en"}},
+			{"GET", "/api/me/sessions", nil},
+			{"GET", "/api/api-keys", nil},
+			{"GET", "/api/me/workflow", nil},
+			{"PUT", "/api/me/workflow", workflow},
+			{"DELETE", "/api/me/workflow", nil},
+			{"DELETE", fmt.Sprintf("/api/tasks/%d", taskID), nil},
+		}
+
+		for _, r := range requests {
+			w, req := makeAuthenticatedRequest(r.method, r.url, testUser.Token, r.body)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code, "%s %s: %s", r.method, r.url, w.Body.String())
+			assertNoPasswordInResponse(t, w, r.method+" "+r.url)
+		}
+	})
+
+	t.Run("Login con OIDC no expone la contraseña", func(t *testing.T) {
+		authURL, cookie := startOIDCLogin(t, router)
+		code := idp.authorize(authURL, "subject-serialization", jwt.MapClaims{
+			"email":              "testuser_serialization_oidc@test.com",
+			"email_verified":     true,
+			"preferred_username": "testuser_serialization_oidc",
+		})
+
+		w := finishOIDCLogin(router, authURL.Query().Get("state"), code, cookie)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assertNoPasswordInResponse(t, w, "GET /api/auth/oidc/callback")
+	})
+}
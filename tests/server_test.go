@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/server"
+	"go-task-manager-mvc/workers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer arranca un servidor con el handler dado en un puerto libre
+func startTestServer(t *testing.T, cfg config.ServerConfig, handler http.Handler) (*server.Server, string, context.CancelFunc, chan error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := server.New(cfg, handler)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+
+	return srv, "http://" + ln.Addr().String(), cancel, done
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	cfg := config.Default().Server
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("Drenar peticiones en curso y ejecutar la limpieza en orden", func(t *testing.T) {
+		srv, url, cancel, done := startTestServer(t, cfg, slow)
+
+		var order []string
+		srv.OnShutdown(func(ctx context.Context) error { order = append(order, "workers"); return nil })
+		srv.OnShutdown(func(ctx context.Context) error { order = append(order, "db"); return nil })
+
+		status := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(url)
+			if err != nil {
+				status <- 0
+				return
+			}
+			resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+
+		// Pedir el apagado mientras la petición sigue en curso
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		assert.Equal(t, http.StatusOK, <-status)
+		assert.NoError(t, <-done)
+		assert.Equal(t, []string{"workers", "db"}, order)
+
+		_, err := http.Get(url)
+		assert.Error(t, err, "no debería aceptar conexiones nuevas")
+	})
+
+	t.Run("Cortar las peticiones que superan el plazo de apagado", func(t *testing.T) {
+		short := cfg
+		short.ShutdownTimeout = config.Duration(50 * time.Millisecond)
+		srv, url, cancel, done := startTestServer(t, short, slow)
+
+		cleaned := false
+		var cleanupErr error
+		srv.OnShutdown(func(ctx context.Context) error {
+			cleaned = true
+			cleanupErr = ctx.Err()
+			return nil
+		})
+
+		go http.Get(url)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+		assert.True(t, cleaned, "la limpieza se ejecuta aunque venza el plazo")
+		assert.NoError(t, cleanupErr, "la limpieza tiene su propio plazo")
+	})
+}
+
+func TestBackgroundWorkers(t *testing.T) {
+	t.Run("Detener los workers espera a que terminen", func(t *testing.T) {
+		var runs atomic.Int32
+		var stopped atomic.Bool
+		manager := workers.NewManager(workers.Every("contador", 10*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}), stoppableWorker{stopped: &stopped})
+
+		manager.Start()
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(t, manager.Stop(context.Background()))
+		assert.GreaterOrEqual(t, runs.Load(), int32(2))
+		assert.True(t, stopped.Load())
+	})
+
+	t.Run("Limpieza de sesiones inactivas", func(t *testing.T) {
+		repos, store := newMemoryRepositories()
+		now := time.Now()
+		old := now.Add(-48 * time.Hour)
+
+		active := models.Session{UserID: 1, ExpiresAt: now.Add(time.Hour)}
+		expired := models.Session{UserID: 1, ExpiresAt: old}
+		revoked := models.Session{UserID: 1, ExpiresAt: now.Add(time.Hour), RevokedAt: &old}
+		recentlyRevoked := models.Session{UserID: 1, ExpiresAt: now.Add(time.Hour), RevokedAt: &now}
+		for _, session := range []*models.Session{&active, &expired, &revoked, &recentlyRevoked} {
+			require.NoError(t, repos.Sessions.Create(context.Background(), session))
+		}
+
+		manager := workers.NewManager(workers.NewSessionCleanup(repos.Sessions, time.Hour, 24*time.Hour))
+		manager.Start()
+		require.Eventually(t, func() bool {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			return len(store.sessions) == 2
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, manager.Stop(context.Background()))
+
+		assert.Contains(t, store.sessions, active.ID)
+		assert.Contains(t, store.sessions, recentlyRevoked.ID)
+	})
+}
+
+// stoppableWorker marca que terminó después de que se cancela su contexto
+type stoppableWorker struct {
+	stopped *atomic.Bool
+}
+
+func (w stoppableWorker) Name() string { return "stoppable" }
+
+func (w stoppableWorker) Run(ctx context.Context) {
+	<-ctx.Done()
+	time.Sleep(20 * time.Millisecond)
+	w.stopped.Store(true)
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFakeJWTKeys firma tokens con un secreto de prueba sin leer la configuración del entorno
+func loadFakeJWTKeys(t *testing.T) {
+	cfg := config.Default().JWT
+	cfg.Secret = "fake-secret"
+	require.NoError(t, config.LoadJWTKeys(cfg))
+}
+
+// setupFakeRouter construye el router sobre repositorios en memoria, sin base de datos
+func setupFakeRouter(t *testing.T) *gin.Engine {
+	loadFakeJWTKeys(t)
+
+	repos, _ := newMemoryRepositories()
+	r := gin.New()
+	routes.SetupRoutes(r, repos)
+	return r
+}
+
+func TestTaskServiceWithFakes(t *testing.T) {
+	repos, _ := newMemoryRepositories()
+	tasks := services.NewTaskService(repos)
+	ctx := context.Background()
+
+	t.Run("Crear tarea aplica valores por defecto y recorta espacios", func(t *testing.T) {
+		task, err := tasks.Create(ctx, 1, models.TaskCreateRequest{Title: "  Tarea en memoria  "})
+
+		require.NoError(t, err)
+		assert.NotZero(t, task.ID)
+		assert.Equal(t, "Tarea en memoria", task.Title)
+		assert.Equal(t, models.TaskStatusPending, task.Status)
+	})
+
+	t.Run("Rechazar tarea sin título como error de validación", func(t *testing.T) {
+		_, err := tasks.Create(ctx, 1, models.TaskCreateRequest{Title: "   "})
+
+		var validationErr *services.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Filtrar por estado inválido", func(t *testing.T) {
+		_, err := tasks.List(ctx, 1, "archivada")
+		assert.ErrorIs(t, err, services.ErrInvalidStatus)
+	})
+
+	t.Run("No modificar ni eliminar tareas de otro usuario", func(t *testing.T) {
+		task, err := tasks.Create(ctx, 1, models.TaskCreateRequest{Title: "Tarea ajena"})
+		require.NoError(t, err)
+
+		_, err = tasks.Update(ctx, 2, task.ID, models.TaskUpdateRequest{Status: models.TaskStatusCompleted})
+		assert.ErrorIs(t, err, services.ErrTaskNotFound)
+
+		_, err = tasks.Delete(ctx, 2, task.ID)
+		assert.ErrorIs(t, err, services.ErrTaskNotFound)
+	})
+
+	t.Run("Actualizar y listar por estado", func(t *testing.T) {
+		task, err := tasks.Create(ctx, 3, models.TaskCreateRequest{Title: "Tarea a completar"})
+		require.NoError(t, err)
+
+		_, err = tasks.Update(ctx, 3, task.ID, models.TaskUpdateRequest{Status: models.TaskStatusCompleted})
+		require.NoError(t, err)
+
+		completed, err := tasks.List(ctx, 3, models.TaskStatusCompleted)
+		require.NoError(t, err)
+		assert.Len(t, completed, 1)
+
+		pending, err := tasks.List(ctx, 3, models.TaskStatusPending)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
+
+func TestAuthServiceWithFakes(t *testing.T) {
+	loadFakeJWTKeys(t)
+
+	repos, store := newMemoryRepositories()
+	auth := services.NewAuthService(repos)
+	ctx := context.Background()
+	client := services.ClientInfo{UserAgent: "tests", IP: "127.0.0.1"}
+
+	t.Run("Registrar y abrir sesión", func(t *testing.T) {
+		user, err := auth.Register(ctx, services.RegisterInput{
+			Username: "fake_user",
+			Email:    "fake_user@test.com",
+			Password: "Tareas-Prueba-2024",
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, "Tareas-Prueba-2024", user.Password)
+
+		token, logged, err := auth.Login(ctx, "fake_user@test.com", "Tareas-Prueba-2024", client)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, logged.ID)
+		assert.Len(t, store.sessions, 1)
+
+		claims, err := config.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "fake_user", claims.Username)
+	})
+
+	t.Run("Credenciales incorrectas", func(t *testing.T) {
+		_, _, err := auth.Login(ctx, "fake_user@test.com", "otra-contraseña", client)
+		assert.ErrorIs(t, err, services.ErrWrongPassword)
+
+		_, _, err = auth.Login(ctx, "nadie@test.com", "Tareas-Prueba-2024", client)
+		assert.ErrorIs(t, err, services.ErrUserNotFound)
+	})
+
+	t.Run("Contraseña que no cumple la política", func(t *testing.T) {
+		_, err := auth.Register(ctx, services.RegisterInput{
+			Username: "fake_weak",
+			Email:    "fake_weak@test.com",
+			Password: "123",
+		})
+
+		var policyErr *services.PasswordPolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.NotEmpty(t, policyErr.Violations)
+	})
+
+	t.Run("Identidad externa con nombre de usuario ocupado", func(t *testing.T) {
+		identity := services.ExternalIdentity{
+			Issuer:            "https://idp.test",
+			Subject:           "subject-1",
+			Email:             "otra@test.com",
+			PreferredUsername: "fake_user",
+		}
+
+		_, _, err := auth.LoginWithIdentity(ctx, identity, false, client)
+		assert.ErrorIs(t, err, services.ErrSignupDisabled)
+
+		_, user, err := auth.LoginWithIdentity(ctx, identity, true, client)
+		require.NoError(t, err)
+		assert.Equal(t, "fake_user_2", user.Username)
+
+		// El segundo login reutiliza el vínculo
+		_, again, err := auth.LoginWithIdentity(ctx, identity, false, client)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, again.ID)
+	})
+}
+
+func TestHandlersWithFakeRepositories(t *testing.T) {
+	router := setupFakeRouter(t)
+
+	testUser := createTestUser(t, router, "fake_handlers")
+
+	t.Run("Crear y listar tareas sin base de datos", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("POST", "/api/tasks", testUser.Token, map[string]interface{}{
+			"title": "Tarea en memoria",
+		})
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w, req = makeAuthenticatedRequest("GET", "/api/tasks", testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, float64(1), response["count"])
+	})
+
+	t.Run("Tarea inexistente", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("DELETE", "/api/tasks/999", testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Token de una sesión revocada", func(t *testing.T) {
+		w, req := makeAuthenticatedRequest("DELETE", "/api/me/sessions", testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w, req = makeAuthenticatedRequest("GET", "/api/tasks", testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-task-manager-mvc/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// loginWithUserAgent inicia sesión desde un "dispositivo" concreto y retorna el token
+func loginWithUserAgent(t *testing.T, router *gin.Engine, user TestUser, userAgent string) string {
+	body, _ := json.Marshal(map[string]string{
+		"email":    user.Email,
+		"password": user.Password,
+	})
+	req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	token, _ := response["token"].(string)
+	if token == "" {
+		t.Fatalf("login fallido: %s", w.Body.String())
+	}
+	return token
+}
+
+// listSessions devuelve las sesiones activas visibles con el token indicado
+func listSessions(t *testing.T, router *gin.Engine, token string) []map[string]interface{} {
+	w, req := makeAuthenticatedRequest("GET", "/api/me/sessions", token, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Sessions []map[string]interface{} `json:"sessions"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response.Sessions
+}
+
+// statusWithToken retorna el código de respuesta de GET /api/tasks con el token indicado
+func statusWithToken(router *gin.Engine, token string) int {
+	w, req := makeAuthenticatedRequest("GET", "/api/tasks", token, nil)
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestSessions(t *testing.T) {
+	setupTestDB()
+	router := setupRouter()
+	defer cleanupTestData()
+
+	testUser := createTestUser(t, router, "testuser_sessions")
+
+	t.Run("Listar sesiones activas", func(t *testing.T) {
+		laptop := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (laptop)")
+		loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (phone)")
+
+		sessions := listSessions(t, router, laptop)
+		assert.GreaterOrEqual(t, len(sessions), 2)
+
+		current := 0
+		for _, session := range sessions {
+			assert.NotEmpty(t, session["last_seen_at"])
+			if session["current"] == true {
+				current++
+				assert.Equal(t, "TestBrowser/1.0 (laptop)", session["user_agent"])
+			}
+		}
+		assert.Equal(t, 1, current)
+	})
+
+	t.Run("Cerrar una sesión invalida su token", func(t *testing.T) {
+		laptop := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (laptop)")
+		phone := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (phone)")
+
+		phoneClaims, _ := config.ValidateToken(phone)
+		w, req := makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/me/sessions/%d", phoneClaims.SessionID), laptop, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, phone))
+		assert.Equal(t, http.StatusOK, statusWithToken(router, laptop))
+	})
+
+	t.Run("No cerrar sesiones de otro usuario", func(t *testing.T) {
+		other := createTestUser(t, router, "testuser_sessions_other")
+		otherClaims, _ := config.ValidateToken(other.Token)
+
+		w, req := makeAuthenticatedRequest("DELETE", fmt.Sprintf("/api/me/sessions/%d", otherClaims.SessionID), testUser.Token, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusOK, statusWithToken(router, other.Token))
+	})
+
+	t.Run("Cerrar sesión en todos lados excepto la actual", func(t *testing.T) {
+		laptop := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (laptop)")
+		phone := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (phone)")
+
+		w, req := makeAuthenticatedRequest("DELETE", "/api/me/sessions?except_current=true", laptop, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, phone))
+		assert.Equal(t, http.StatusOK, statusWithToken(router, laptop))
+		assert.Len(t, listSessions(t, router, laptop), 1)
+	})
+
+	t.Run("Cerrar sesión en todos lados", func(t *testing.T) {
+		laptop := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (laptop)")
+		phone := loginWithUserAgent(t, router, testUser, "TestBrowser/1.0 (phone)")
+
+		w, req := makeAuthenticatedRequest("DELETE", "/api/me/sessions", laptop, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, phone))
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, laptop))
+	})
+
+	t.Run("Rechazar tokens sin sesión", func(t *testing.T) {
+		token, _ := config.GenerateToken(testUser.Username, 0)
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, token))
+	})
+
+	t.Run("Rechazar tokens con la sesión de otro usuario", func(t *testing.T) {
+		other := createTestUser(t, router, "testuser_sessions_forged")
+		otherClaims, _ := config.ValidateToken(other.Token)
+
+		token, _ := config.GenerateToken(testUser.Username, otherClaims.SessionID)
+		assert.Equal(t, http.StatusUnauthorized, statusWithToken(router, token))
+	})
+}
@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsResponse es la respuesta de GET /api/stats
+type statsResponse struct {
+	Timezone          string   `json:"timezone"`
+	Total             int64    `json:"total"`
+	Completed         int64    `json:"completed"`
+	Overdue           int64    `json:"overdue"`
+	CompletionRate    float64  `json:"completion_rate"`
+	AverageCompletion *float64 `json:"average_completion_seconds"`
+	ByStatus          []struct {
+		Status string `json:"status"`
+		Label  string `json:"label"`
+		Count  int64  `json:"count"`
+	} `json:"by_status"`
+	Upcoming map[string]int64 `json:"upcoming"`
+	Activity struct {
+		Interval string `json:"interval"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Periods  []struct {
+			Period    string `json:"period"`
+			Created   int64  `json:"created"`
+			Completed int64  `json:"completed"`
+		} `json:"periods"`
+	} `json:"activity"`
+}
+
+func TestStatsRange(t *testing.T) {
+	now := time.Date(2026, 10, 28, 23, 30, 0, 0, time.UTC)
+
+	t.Run("Por defecto los últimos 30 días en UTC", func(t *testing.T) {
+		statsRange, err := models.StatsRequest{}.Resolve(now)
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, statsRange.Location)
+		assert.Len(t, statsRange.Periods, models.StatsDefaultDays+1)
+		from, to := statsRange.Dates()
+		assert.Equal(t, "2026-09-29", from)
+		assert.Equal(t, "2026-10-28", to)
+	})
+
+	t.Run("Los días empiezan a medianoche de la zona horaria del usuario", func(t *testing.T) {
+		statsRange, err := models.StatsRequest{Timezone: "Europe/Madrid"}.Resolve(now)
+		require.NoError(t, err)
+		_, to := statsRange.Dates()
+		assert.Equal(t, "2026-10-29", to, "las 23:30 UTC ya son el día siguiente en Madrid")
+		assert.Equal(t, time.Date(2026, 10, 28, 23, 0, 0, 0, time.UTC), statsRange.Periods[len(statsRange.Periods)-2].UTC())
+	})
+
+	t.Run("Las semanas empiezan en lunes y respetan el cambio de hora", func(t *testing.T) {
+		statsRange, err := models.StatsRequest{
+			From: "2026-10-21", To: "2026-10-28", Interval: models.StatsIntervalWeek, Timezone: "Europe/Madrid",
+		}.Resolve(now)
+		require.NoError(t, err)
+		require.Len(t, statsRange.Periods, 3)
+		assert.Equal(t, "2026-10-19", statsRange.PeriodLabel(0))
+		// El 25 de octubre Madrid pasa de UTC+2 a UTC+1
+		assert.Equal(t, time.Date(2026, 10, 18, 22, 0, 0, 0, time.UTC), statsRange.Periods[0].UTC())
+		assert.Equal(t, time.Date(2026, 10, 25, 23, 0, 0, 0, time.UTC), statsRange.Periods[1].UTC())
+	})
+
+	t.Run("Validar los parámetros", func(t *testing.T) {
+		_, err := models.StatsRequest{From: "21/10/2026", Interval: "month", Timezone: "Marte/Olympus"}.Resolve(now)
+		assert.ElementsMatch(t, []string{"from:date", "interval:oneof", "tz:timezone"}, validationCodes(t, err))
+
+		_, err = models.StatsRequest{From: "2026-10-28", To: "2026-10-01"}.Resolve(now)
+		assert.Equal(t, []string{"to:range"}, validationCodes(t, err))
+
+		_, err = models.StatsRequest{From: "2025-01-01", To: "2026-10-28"}.Resolve(now)
+		assert.Equal(t, []string{"from:range"}, validationCodes(t, err))
+	})
+}
+
+func TestStatsAPI(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_stats")
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		return serveRequest(router, method, url, user.Token, body, "Accept-Language", "es")
+	}
+
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	today := time.Now().In(madrid)
+	tomorrow := time.Date(today.Year(), today.Month(), today.Day()+1, 12, 0, 0, 0, madrid)
+
+	tasks := []string{
+		fmt.Sprintf(`{"title":"TEST mañana","due_date":%q}`, tomorrow.Format(time.RFC3339)),
+		`{"title":"TEST sin fecha"}`,
+		`{"title":"TEST completada"}`,
+	}
+	var lastID float64
+	for _, body := range tasks {
+		w := request(http.MethodPost, "/api/tasks", body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		lastID = decodeTask(t, w)["id"].(float64)
+	}
+	w := request(http.MethodPut, fmt.Sprintf("/api/tasks/%d", int(lastID)), `{"status":"completada"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	t.Run("Resumen de las tareas del usuario", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/stats?tz=Europe/Madrid", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var stats statsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "Europe/Madrid", stats.Timezone)
+		assert.Equal(t, int64(3), stats.Total)
+		assert.Equal(t, int64(1), stats.Completed)
+		assert.Equal(t, 0.333, stats.CompletionRate)
+		assert.NotNil(t, stats.AverageCompletion)
+
+		require.Len(t, stats.ByStatus, 3)
+		assert.Equal(t, "Pendiente", stats.ByStatus[0].Label)
+		assert.Equal(t, int64(2), stats.ByStatus[0].Count)
+		assert.Equal(t, int64(0), stats.ByStatus[1].Count, "los estados sin tareas también aparecen")
+
+		assert.Equal(t, map[string]int64{
+			"overdue": 0, "today": 0, "tomorrow": 1, "next_7_days": 0, "later": 0, "no_due_date": 1,
+		}, stats.Upcoming)
+
+		assert.Equal(t, models.StatsIntervalDay, stats.Activity.Interval)
+		require.Len(t, stats.Activity.Periods, models.StatsDefaultDays)
+		last := stats.Activity.Periods[len(stats.Activity.Periods)-1]
+		assert.Equal(t, today.Format("2006-01-02"), last.Period)
+		assert.Equal(t, int64(3), last.Created)
+		assert.Equal(t, int64(1), last.Completed)
+	})
+
+	t.Run("Agrupar por semanas", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/stats?interval=week&from=2026-10-01&to=2026-10-31", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var stats statsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "2026-09-28", stats.Activity.From)
+		assert.Equal(t, "2026-11-01", stats.Activity.To)
+		assert.Len(t, stats.Activity.Periods, 5)
+	})
+
+	t.Run("Sin tz se usa la zona horaria de las preferencias", func(t *testing.T) {
+		w := request(http.MethodPut, "/api/me/preferences", `{"timezone":"America/Bogota"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"timezone":"America/Bogota"`)
+		defer request(http.MethodPut, "/api/me/preferences", `{"timezone":""}`)
+
+		var stats statsResponse
+		w = request(http.MethodGet, "/api/stats", "")
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "America/Bogota", stats.Timezone)
+
+		w = request(http.MethodGet, "/api/stats?tz=Europe/Madrid", "")
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "Europe/Madrid", stats.Timezone, "tz tiene prioridad")
+	})
+
+	t.Run("Validar la zona horaria de las preferencias", func(t *testing.T) {
+		w := request(http.MethodPut, "/api/me/preferences", `{"timezone":"Marte/Olympus","language":"fr"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.ElementsMatch(t, []string{"timezone:timezone", "language:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+
+		w = request(http.MethodPut, "/api/me/preferences", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.ElementsMatch(t, []string{"language:required", "timezone:required"}, fieldCodes(decodeProblem(t, w).Errors))
+	})
+
+	t.Run("Rechazar parámetros inválidos", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/stats?tz=Nowhere&interval=year", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.ElementsMatch(t, []string{"tz:timezone", "interval:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+	})
+}
+
+func TestStatsRepository(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_stats_db")
+	repos := repositories.NewGormRepositories(config.DB)
+
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, madrid) }
+	utc := func(d, hour, minute int) time.Time { return time.Date(2026, 3, d, hour, minute, 0, 0, time.UTC) }
+	past, started, completed := utc(1, 9, 0), utc(10, 11, 0), utc(10, 12, 0)
+
+	require.NoError(t, config.DB.Create(&[]models.Task{
+		// 23:30 UTC del día 10 ya es el día 11 en Madrid
+		{Title: "TEST medianoche", Status: models.TaskStatusPending, UserID: user.ID, CreatedAt: utc(10, 23, 30), UpdatedAt: utc(10, 23, 30)},
+		{Title: "TEST completada", Status: models.TaskStatusCompleted, UserID: user.ID, CreatedAt: utc(10, 10, 0), UpdatedAt: utc(10, 13, 0),
+			StartedAt: &started, CompletedAt: &completed},
+		{Title: "TEST vencida", Status: models.TaskStatusInProgress, UserID: user.ID, DueDate: &past, CreatedAt: utc(1, 8, 0), UpdatedAt: utc(1, 8, 0)},
+	}).Error)
+
+	stats, err := repos.Tasks.Stats(context.Background(), repositories.TaskStatsQuery{
+		UserID:       user.ID,
+		DoneStatuses: []string{models.TaskStatusCompleted},
+		Periods:      []time.Time{day(10), day(11), day(12)},
+		DueBounds:    []time.Time{utc(10, 0, 0)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{
+		models.TaskStatusPending: 1, models.TaskStatusInProgress: 1, models.TaskStatusCompleted: 1,
+	}, stats.ByStatus)
+	assert.Equal(t, []int64{1, 1}, stats.Created)
+	assert.Equal(t, []int64{1, 0}, stats.Completed)
+	require.NotNil(t, stats.AvgCompletionSeconds)
+	assert.Equal(t, float64(2*60*60), *stats.AvgCompletionSeconds)
+	require.NotNil(t, stats.AvgCycleSeconds)
+	assert.Equal(t, float64(60*60), *stats.AvgCycleSeconds)
+	assert.Equal(t, []int64{1, 0}, stats.Due)
+	assert.Equal(t, int64(1), stats.NoDueDate)
+}
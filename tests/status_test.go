@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskStatuses(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repositories.NewGormRepositories(config.DB))
+	user := createTestUser(t, router, "testuser_statuses")
+
+	t.Run("Normalizar los alias en español", func(t *testing.T) {
+		cases := map[string]string{
+			"pendiente":     models.TaskStatusPending,
+			" En Progreso ": models.TaskStatusInProgress,
+			"en_progreso":   models.TaskStatusInProgress,
+			"completada":    models.TaskStatusCompleted,
+			"IN_PROGRESS":   models.TaskStatusInProgress,
+			"archivada":     "archivada",
+		}
+		for input, expected := range cases {
+			assert.Equal(t, expected, models.NormalizeTaskStatus(input), "estado %q", input)
+		}
+	})
+
+	t.Run("Guardar el identificador aunque se envíe el alias", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"TEST alias","status":"en progreso"}`, "Accept-Language", "en")
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response map[string]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, models.TaskStatusInProgress, response["task"]["status"])
+		assert.Equal(t, "In progress", response["task"]["status_label"])
+
+		var task models.Task
+		require.NoError(t, config.DB.First(&task, response["task"]["id"]).Error)
+		assert.Equal(t, models.TaskStatusInProgress, task.Status)
+	})
+
+	t.Run("Rechazar estados desconocidos con la lista de identificadores", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, "/api/tasks", user.Token, `{"title":"TEST estado inválido","status":"archivada"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		response := decodeProblem(t, w)
+		assert.Equal(t, []string{"status:oneof"}, fieldCodes(response.Errors))
+		assert.Contains(t, response.Errors[0].Message, "pending, in_progress, completed")
+	})
+
+	t.Run("Listar los estados con su etiqueta traducida", func(t *testing.T) {
+		labels := map[string][]string{
+			"es": {"Pendiente", "En progreso", "Completada"},
+			"en": {"Pending", "In progress", "Completed"},
+		}
+		for language, expected := range labels {
+			w := serveRequest(router, http.MethodGet, "/api/task-statuses", user.Token, "", "Accept-Language", language)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				Statuses []struct {
+					ID    string `json:"id"`
+					Label string `json:"label"`
+					Color string `json:"color"`
+				} `json:"statuses"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.Len(t, response.Statuses, 3)
+			for i, status := range response.Statuses {
+				assert.Equal(t, models.GetValidTasksStatuesList()[i], status.ID)
+				assert.Equal(t, expected[i], status.Label)
+				assert.NotEqual(t, "gray", status.Color)
+			}
+		}
+	})
+}
+
+func TestCanonicalStatusMigration(t *testing.T) {
+	setupTestDB()
+	ctx := context.Background()
+
+	db := openEmptyDatabase(t, "migrations_statuses")
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec("INSERT INTO users (username, email, password) VALUES ('estados', 'estados@example.com', 'x')").Error)
+	require.NoError(t, db.Exec(`INSERT INTO tasks (title, status, user_id) VALUES
+		('a', 'pending', 1), ('b', 'in_progress', 1), ('c', 'completed', 1), ('d', 'otro', 1)`).Error)
+
+	statuses := func() []string {
+		var result []string
+		require.NoError(t, db.Raw("SELECT status FROM tasks ORDER BY title").Scan(&result).Error)
+		return result
+	}
+
+	// Revertir hasta antes de la migración 0007 devuelve los textos en español
+	steps := 0
+	for _, migration := range applied {
+		if migration.Version >= 7 {
+			steps++
+		}
+	}
+	_, err = migrator.Down(ctx, steps)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pendiente", "en progreso", "completada", "otro"}, statuses())
+
+	// Y al aplicarla se convierten los datos existentes
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pending", "in_progress", "completed", "otro"}, statuses())
+
+	require.NoError(t, db.Exec("INSERT INTO tasks (title, user_id) VALUES ('e', 1)").Error)
+	assert.Equal(t, "pending", statuses()[4])
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+	"go-task-manager-mvc/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Traza y span de un servicio que llama a la API (formato W3C traceparent)
+const (
+	upstreamTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	upstreamSpanID      = "00f067aa0ba902b7"
+	upstreamTraceparent = "00-" + upstreamTraceID + "-" + upstreamSpanID + "-01"
+)
+
+// installTestTracer instala un proveedor que guarda los spans en memoria mientras dure el test
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := config.Default().Tracing
+	_, err := tracing.Setup(context.Background(), cfg)
+	require.NoError(t, err)
+	provider, err := tracing.NewProvider(context.Background(), cfg, sdktrace.NewSimpleSpanProcessor(exporter))
+	require.NoError(t, err)
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+// findSpan retorna el span con ese nombre o falla el test
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	require.FailNow(t, "span no encontrado", "%s no está entre %v", name, names)
+	return tracetest.SpanStub{}
+}
+
+func spanAttribute(span tracetest.SpanStub, key string) string {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracing(t *testing.T) {
+	exporter := installTestTracer(t)
+
+	t.Run("La petición continúa la traza del traceparent recibido", func(t *testing.T) {
+		exporter.Reset()
+		logs := captureLogs(t, "info")
+		loadFakeJWTKeys(t)
+		repos, _ := newMemoryRepositories()
+		r := gin.New()
+		r.Use(middleware.Tracing("go-task-api"), middleware.RequestID(), middleware.AccessLog())
+		routes.SetupRoutes(r, repos)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		req.Header.Set("traceparent", upstreamTraceparent)
+		req.Header.Set("Authorization", "Bearer token-invalido")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		spans := exporter.GetSpans()
+		server := findSpan(t, spans, "GET /api/tasks")
+		assert.Equal(t, upstreamTraceID, server.SpanContext.TraceID().String())
+		assert.Equal(t, upstreamSpanID, server.Parent.SpanID().String())
+		assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+		assert.Equal(t, "/api/tasks", spanAttribute(server, "http.route"))
+
+		// La autenticación y la validación del JWT cuelgan del span de la petición
+		auth := findSpan(t, spans, "AuthMiddleware")
+		assert.Equal(t, server.SpanContext.SpanID(), auth.Parent.SpanID())
+		assert.Equal(t, "false", spanAttribute(auth, "auth.authenticated"))
+		validate := findSpan(t, spans, "ValidateToken")
+		assert.Equal(t, auth.SpanContext.SpanID(), validate.Parent.SpanID())
+
+		// El log de acceso permite saltar de la línea de log a la traza
+		entries := accessLogEntries(t, logs)
+		require.Len(t, entries, 1)
+		assert.Equal(t, upstreamTraceID, entries[0]["trace_id"])
+	})
+
+	t.Run("Las rutas de sondeo no se trazan", func(t *testing.T) {
+		exporter.Reset()
+		r := gin.New()
+		r.Use(middleware.Tracing("go-task-api"))
+		r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, exporter.GetSpans())
+	})
+
+	t.Run("Cada sentencia SQL es un span hijo de la petición", func(t *testing.T) {
+		setupTestDB()
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		require.NoError(t, config.CloseDB())
+		require.NoError(t, config.ConnectDB(cfg.Database, tracing.NewGormPlugin()))
+		cleanupTestData()
+		defer cleanupTestData()
+
+		r := gin.New()
+		r.Use(middleware.Tracing("go-task-api"))
+		routes.SetupRoutes(r, repositories.NewGormRepositories(config.DB))
+		user := createTestUser(t, r, "testuser_tracing")
+		require.NotEmpty(t, user.Token)
+
+		exporter.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+user.Token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		spans := exporter.GetSpans()
+		server := findSpan(t, spans, "GET /api/tasks")
+		auth := findSpan(t, spans, "AuthMiddleware")
+		assert.Equal(t, "jwt", spanAttribute(auth, "auth.method"))
+
+		// La consulta de la sesión ocurre durante la autenticación
+		session := findSpan(t, spans, "SELECT sessions")
+		assert.Equal(t, auth.SpanContext.SpanID(), session.Parent.SpanID())
+
+		// La consulta de tareas ocurre en el controlador
+		tasks := findSpan(t, spans, "SELECT tasks")
+		assert.Equal(t, server.SpanContext.TraceID(), tasks.SpanContext.TraceID())
+		assert.Equal(t, server.SpanContext.SpanID(), tasks.Parent.SpanID())
+		assert.Equal(t, trace.SpanKindClient, tasks.SpanKind)
+		assert.Equal(t, "mysql", spanAttribute(tasks, "db.system.name"))
+		assert.Equal(t, "tasks", spanAttribute(tasks, "db.collection.name"))
+		assert.Contains(t, spanAttribute(tasks, "db.query.text"), "FROM `tasks`")
+		assert.NotContains(t, spanAttribute(tasks, "db.query.text"), "testuser_tracing")
+	})
+
+	t.Run("Una sentencia fallida marca su span como error", func(t *testing.T) {
+		exporter.Reset()
+		ctx, parent := tracing.Start(context.Background(), "test")
+		err := config.DB.WithContext(ctx).Exec("SELECT * FROM tabla_inexistente").Error
+		parent.End()
+		require.Error(t, err)
+
+		span := findSpan(t, exporter.GetSpans(), "SELECT")
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	})
+}
@@ -0,0 +1,339 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/migrations"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transitionsResponse es la respuesta de GET /api/tasks/:id/transitions
+type transitionsResponse struct {
+	Task struct {
+		StartedAt   *time.Time `json:"started_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+		LeadTime    *int64     `json:"lead_time_seconds"`
+		CycleTime   *int64     `json:"cycle_time_seconds"`
+	} `json:"task"`
+	Transitions []struct {
+		From     *string `json:"from"`
+		To       string  `json:"to"`
+		ToLabel  string  `json:"to_label"`
+		Duration *int64  `json:"duration_seconds"`
+	} `json:"transitions"`
+}
+
+func TestTaskStatusTimes(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return created.Add(time.Duration(hours) * time.Hour) }
+
+	t.Run("Las tareas nuevas registran su estado inicial", func(t *testing.T) {
+		task := models.Task{Title: "Tarea", UserID: 1}
+		require.NoError(t, task.Validate())
+
+		transition := task.RecordStatusChange(created)
+		require.NotNil(t, transition)
+		assert.Equal(t, "", transition.From)
+		assert.Equal(t, models.TaskStatusPending, transition.To)
+		assert.Nil(t, task.StartedAt)
+		assert.Nil(t, task.CompletedAt)
+	})
+
+	t.Run("Los cambios de estado mantienen el inicio y la finalización", func(t *testing.T) {
+		task := models.Task{ID: 1, Title: "Tarea", Status: models.TaskStatusPending, UserID: 1, CreatedAt: created}
+		task.UseWorkflow(models.DefaultWorkflow())
+		assert.Nil(t, task.RecordStatusChange(at(1)), "sin cambio de estado no hay transición")
+
+		task.Status = models.TaskStatusInProgress
+		transition := task.RecordStatusChange(at(2))
+		require.NotNil(t, transition)
+		assert.Equal(t, models.TaskStatusPending, transition.From)
+		assert.Equal(t, at(2), *task.StartedAt)
+
+		task.Status = models.TaskStatusCompleted
+		task.RecordStatusChange(at(5))
+		assert.Equal(t, at(2), *task.StartedAt, "el inicio no cambia al completar")
+		assert.Equal(t, at(5), *task.CompletedAt)
+		assert.Equal(t, 5*time.Hour, *task.LeadTime())
+		assert.Equal(t, 3*time.Hour, *task.CycleTime())
+
+		// Reabrir borra la finalización y volver al estado inicial borra el inicio
+		task.Status = models.TaskStatusInProgress
+		task.RecordStatusChange(at(6))
+		assert.Nil(t, task.CompletedAt)
+		assert.Nil(t, task.LeadTime())
+		assert.Equal(t, at(2), *task.StartedAt)
+
+		task.Status = models.TaskStatusPending
+		task.RecordStatusChange(at(7))
+		assert.Nil(t, task.StartedAt)
+	})
+
+	t.Run("MarkAsCompleted fija la finalización", func(t *testing.T) {
+		task := models.Task{ID: 1, Title: "Tarea", Status: models.TaskStatusPending, UserID: 1, CreatedAt: created}
+		task.UseWorkflow(models.DefaultWorkflow())
+		task.MarkAsCompleted()
+		require.NotNil(t, task.CompletedAt)
+		require.NotNil(t, task.StartedAt)
+		assert.Equal(t, time.Duration(0), *task.CycleTime(), "pasar directamente a completada no suma tiempo de ciclo")
+
+		// Al guardarla se registra la transición sin cambiar la finalización
+		completedAt := *task.CompletedAt
+		transition := task.RecordStatusChange(time.Now())
+		require.NotNil(t, transition)
+		assert.Equal(t, models.TaskStatusCompleted, transition.To)
+		assert.Equal(t, completedAt, *task.CompletedAt)
+	})
+}
+
+func TestTaskTransitionsAPI(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_transitions")
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		return serveRequest(router, method, url, user.Token, body, "Accept-Language", "es")
+	}
+
+	w := request(http.MethodPost, "/api/tasks", `{"title":"TEST ciclo"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	task := decodeTask(t, w)
+	id := int(task["id"].(float64))
+	assert.Nil(t, task["started_at"])
+	assert.Nil(t, task["lead_time_seconds"])
+
+	w = request(http.MethodPut, fmt.Sprintf("/api/tasks/%d", id), `{"status":"in_progress"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	task = decodeTask(t, w)
+	assert.NotNil(t, task["started_at"])
+	assert.Nil(t, task["completed_at"])
+
+	// Cambiar otros campos no registra transiciones
+	w = request(http.MethodPut, fmt.Sprintf("/api/tasks/%d", id), `{"description":"sin cambio de estado"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = request(http.MethodPost, fmt.Sprintf("/api/tasks/%d/move", id), `{"status":"completed"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotNil(t, decodeTask(t, w)["completed_at"])
+
+	t.Run("Historial de estados de la tarea", func(t *testing.T) {
+		w := request(http.MethodGet, fmt.Sprintf("/api/tasks/%d/transitions", id), "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response transitionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Task.CompletedAt)
+		require.NotNil(t, response.Task.LeadTime)
+		require.NotNil(t, response.Task.CycleTime)
+		assert.LessOrEqual(t, *response.Task.CycleTime, *response.Task.LeadTime)
+
+		require.Len(t, response.Transitions, 3)
+		assert.Nil(t, response.Transitions[0].From)
+		assert.Equal(t, models.TaskStatusPending, response.Transitions[0].To)
+		assert.Equal(t, models.TaskStatusPending, *response.Transitions[1].From)
+		assert.Equal(t, "En progreso", response.Transitions[1].ToLabel)
+		assert.Equal(t, models.TaskStatusCompleted, response.Transitions[2].To)
+		assert.NotNil(t, response.Transitions[1].Duration)
+		assert.Nil(t, response.Transitions[2].Duration, "el estado actual no tiene duración")
+	})
+
+	t.Run("Los tiempos aparecen en el listado", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/tasks", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"cycle_time_seconds":`)
+		assert.NotContains(t, w.Body.String(), `"completed_at":null`)
+	})
+
+	t.Run("Tarea inexistente", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/tasks/9999/transitions", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "task_not_found", decodeProblem(t, w).Code)
+	})
+}
+
+func TestTaskTransitionsWithDatabase(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_transitions_db")
+
+	w, req := makeAuthenticatedRequest("POST", "/api/tasks", user.Token, map[string]interface{}{"title": "TEST historial"})
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	id := uint(decodeTask(t, w)["id"].(float64))
+
+	w, req = makeAuthenticatedRequest("PUT", fmt.Sprintf("/api/tasks/%d", id), user.Token, map[string]interface{}{"status": "completada"})
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var task models.Task
+	require.NoError(t, config.DB.First(&task, id).Error)
+	require.NotNil(t, task.StartedAt)
+	require.NotNil(t, task.CompletedAt)
+
+	var transitions []models.TaskStatusTransition
+	require.NoError(t, config.DB.Where("task_id = ?", id).Order("id").Find(&transitions).Error)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, models.TaskStatusPending, transitions[1].From)
+	assert.Equal(t, models.TaskStatusCompleted, transitions[1].To)
+	assert.Equal(t, user.ID, transitions[1].UserID)
+}
+
+func TestWorkflowChangeSyncsStatusTimes(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_workflow_times")
+	send := func(method, url string, body map[string]interface{}) {
+		w, req := makeAuthenticatedRequest(method, url, user.Token, body)
+		router.ServeHTTP(w, req)
+		require.Less(t, w.Code, http.StatusBadRequest, w.Body.String())
+	}
+
+	ids := make(map[string]uint)
+	for _, status := range []string{models.TaskStatusPending, models.TaskStatusInProgress, models.TaskStatusCompleted} {
+		w, req := makeAuthenticatedRequest("POST", "/api/tasks", user.Token, map[string]interface{}{"title": "TEST " + status})
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		ids[status] = uint(decodeTask(t, w)["id"].(float64))
+		send("PUT", fmt.Sprintf("/api/tasks/%d", ids[status]), map[string]interface{}{"status": status})
+	}
+	times := func(status string) (started, completed bool) {
+		var task models.Task
+		require.NoError(t, config.DB.First(&task, ids[status]).Error)
+		return task.StartedAt != nil, task.CompletedAt != nil
+	}
+
+	// in_progress pasa a ser el estado inicial y pending el único estado completado
+	send("PUT", "/api/me/workflow", map[string]interface{}{"statuses": []map[string]interface{}{
+		{"key": models.TaskStatusInProgress}, {"key": models.TaskStatusPending, "done": true}, {"key": models.TaskStatusCompleted},
+	}})
+	started, completed := times(models.TaskStatusInProgress)
+	assert.False(t, started, "el estado inicial no tiene inicio")
+	assert.False(t, completed)
+	started, completed = times(models.TaskStatusPending)
+	assert.True(t, started)
+	assert.True(t, completed, "pending cuenta como completada")
+	started, completed = times(models.TaskStatusCompleted)
+	assert.True(t, started)
+	assert.False(t, completed, "completed ya no cuenta como completada")
+
+	// Volver al flujo por defecto deshace los cambios
+	send("DELETE", "/api/me/workflow", nil)
+	for status, want := range map[string][2]bool{
+		models.TaskStatusPending:    {false, false},
+		models.TaskStatusInProgress: {true, false},
+		models.TaskStatusCompleted:  {true, true},
+	} {
+		started, completed := times(status)
+		assert.Equal(t, want, [2]bool{started, completed}, status)
+	}
+}
+
+func TestTaskTimestampsMigration(t *testing.T) {
+	setupTestDB()
+	ctx := context.Background()
+
+	db := openEmptyDatabase(t, "migrations_timestamps")
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// El usuario 2 tiene un flujo propio en el que completed no existe y shipped es el estado completado
+	require.NoError(t, db.Exec(`INSERT INTO users (username, email, password) VALUES
+		('por_defecto', 'por_defecto@example.com', 'x'), ('propio', 'propio@example.com', 'x')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO workflow_statuses (user_id, status_key, position, is_done) VALUES
+		(2, 'todo', 0, false), (2, 'review', 1, false), (2, 'shipped', 2, true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tasks (title, status, user_id, created_at, updated_at) VALUES
+		('a', 'completed', 1, '2026-02-11 10:00:00', '2026-03-01 10:00:00'),
+		('b', 'pending', 1, '2026-02-11 10:00:00', '2026-03-01 10:00:00'),
+		('c', 'shipped', 2, '2026-02-12 10:00:00', '2026-03-02 10:00:00'),
+		('d', 'completed', 2, '2026-02-12 10:00:00', '2026-03-02 10:00:00'),
+		('e', 'in_progress', 1, '2026-02-13 10:00:00', '2026-03-03 10:00:00'),
+		('f', 'review', 2, '2026-02-14 10:00:00', '2026-03-04 10:00:00'),
+		('g', 'todo', 2, '2026-02-14 10:00:00', '2026-03-04 10:00:00')`).Error)
+
+	// Dejar aplicada solo la primera migración de las fechas de estado (0013), como si el despliegue
+	// se hubiera interrumpido a mitad, y volver a aplicar el resto
+	steps := 0
+	for _, migration := range applied {
+		if migration.Version > 13 {
+			steps++
+		}
+	}
+	_, err = migrator.Down(ctx, steps)
+	require.NoError(t, err)
+	require.True(t, db.Migrator().HasColumn("tasks", "started_at"))
+	require.False(t, db.Migrator().HasColumn("tasks", "completed_at"))
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	var rows []struct {
+		Title       string
+		StartedAt   *time.Time
+		CompletedAt *time.Time
+	}
+	statusTimes := func() {
+		rows = nil
+		require.NoError(t, db.Raw("SELECT title, started_at, completed_at FROM tasks ORDER BY title").Scan(&rows).Error)
+		require.Len(t, rows, 7)
+	}
+	// day retorna el día del mes de una fecha, 0 si no tiene
+	day := func(value *time.Time) int {
+		if value == nil {
+			return 0
+		}
+		return value.Day()
+	}
+
+	// Las completadas empiezan al crearse y terminan en su última modificación;
+	// las que están en curso empiezan en su última modificación
+	statusTimes()
+	for i, want := range []struct{ started, completed int }{
+		{11, 1}, // completed en el flujo por defecto
+		{0, 0},  // pending es el estado inicial
+		{12, 2}, // estado done del flujo propio
+		{0, 0},  // completed no es un estado del flujo propio
+		{3, 0},  // in_progress en el flujo por defecto
+		{4, 0},  // estado intermedio del flujo propio
+		{0, 0},  // estado inicial del flujo propio
+	} {
+		assert.Equal(t, want.started, day(rows[i].StartedAt), "started_at de %s", rows[i].Title)
+		assert.Equal(t, want.completed, day(rows[i].CompletedAt), "completed_at de %s", rows[i].Title)
+	}
+
+	// El relleno se puede repetir sin pisar las fechas que ya tienen las tareas
+	require.NoError(t, db.Exec("UPDATE tasks SET completed_at = '2026-03-05 12:00:00' WHERE title = 'a'").Error)
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	statusTimes()
+	assert.Equal(t, 5, day(rows[0].CompletedAt))
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"testing"
 
+	"go-task-manager-mvc/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,7 +84,7 @@ func TestTaskValidations(t *testing.T) {
 		json.Unmarshal(w.Body.Bytes(), &response)
 		task := response["task"].(map[string]interface{})
 
-		// El estado por defecto debería ser "pendiente"
-		assert.Equal(t, "pendiente", task["status"])
+		// El estado por defecto debería ser "pending"
+		assert.Equal(t, models.TaskStatusPending, task["status"])
 	})
 }
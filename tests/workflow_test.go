@@ -0,0 +1,283 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-task-manager-mvc/config"
+	"go-task-manager-mvc/middleware"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+	"go-task-manager-mvc/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Flujo de revisión: por hacer → en revisión → hecha, con un estado bloqueada que solo sale hacia revisión
+const reviewWorkflow = `{
+	"statuses": [
+		{"key": "todo", "name": "Por hacer"},
+		{"key": "in_review", "name": "En revisión", "color": "purple"},
+		{"key": "blocked", "name": "Bloqueada", "color": "red"},
+		{"key": "done", "name": "Hecha", "color": "green", "done": true}
+	],
+	"transitions": [
+		{"from": "todo", "to": "in_review"},
+		{"from": "in_review", "to": "done"},
+		{"from": "in_review", "to": "blocked"},
+		{"from": "blocked", "to": "in_review"}
+	]
+}`
+
+// validationCodes retorna "campo:código" de cada error de validación de un modelo
+func validationCodes(t *testing.T, err error) []string {
+	var errs models.ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	codes := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		codes[i] = fieldErr.Field + ":" + fieldErr.Code
+	}
+	return codes
+}
+
+// decodeTask retorna la tarea de una respuesta de creación o actualización
+func decodeTask(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var response struct {
+		Task map[string]interface{} `json:"task"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response.Task
+}
+
+func TestWorkflowModel(t *testing.T) {
+	t.Run("El flujo por defecto permite cualquier transición", func(t *testing.T) {
+		workflow := models.DefaultWorkflow()
+		assert.Equal(t, models.GetValidTasksStatuesList(), workflow.Keys())
+		assert.True(t, workflow.IsDone(models.TaskStatusCompleted))
+		assert.False(t, workflow.IsDone(models.TaskStatusInProgress))
+		assert.True(t, workflow.CanTransition(models.TaskStatusCompleted, models.TaskStatusPending))
+		assert.Equal(t, models.TaskStatusPending, workflow.Initial())
+	})
+
+	t.Run("Validar la definición del flujo", func(t *testing.T) {
+		workflow := &models.Workflow{
+			Statuses:    []models.WorkflowStatus{{Key: "Pendiente"}, {Key: "todo"}, {Key: "todo"}},
+			Transitions: []models.WorkflowTransition{{From: "todo", To: "archivada"}},
+		}
+		assert.ElementsMatch(t, []string{
+			"statuses[0].key:format",
+			"statuses[2].key:unique",
+			"statuses:done_required",
+			"transitions[0].to:oneof",
+		}, validationCodes(t, workflow.Validate()))
+	})
+
+	t.Run("Los alias en español no pueden ser identificadores", func(t *testing.T) {
+		// Al recibir pendiente se guardaría pending, que no existe en este flujo
+		workflow := &models.Workflow{Statuses: []models.WorkflowStatus{
+			{Key: "pendiente"}, {Key: "en_progreso"}, {Key: "completada", Done: true},
+		}}
+		assert.Equal(t, []string{
+			"statuses[0].key:reserved",
+			"statuses[1].key:reserved",
+			"statuses[2].key:reserved",
+		}, validationCodes(t, workflow.Validate()))
+	})
+
+	t.Run("Los estados completados del flujo definen IsCompleted e IsOverdue", func(t *testing.T) {
+		workflow := &models.Workflow{Statuses: []models.WorkflowStatus{
+			{Key: "todo"}, {Key: "shipped", Done: true}, {Key: "cancelled", Done: true},
+		}}
+		yesterday := time.Now().Add(-24 * time.Hour)
+
+		task := models.Task{Title: "Tarea", Status: "cancelled", DueDate: &yesterday, UserID: 1}
+		task.UseWorkflow(workflow)
+		assert.True(t, task.IsCompleted())
+		assert.False(t, task.IsOverdue())
+
+		task.Status = "todo"
+		assert.True(t, task.IsOverdue())
+		task.MarkAsCompleted()
+		assert.Equal(t, "shipped", task.Status)
+	})
+
+	t.Run("Validate rechaza las transiciones que el flujo no permite", func(t *testing.T) {
+		workflow := &models.Workflow{
+			Statuses:    []models.WorkflowStatus{{Key: "todo"}, {Key: "doing"}, {Key: "done", Done: true}},
+			Transitions: []models.WorkflowTransition{{From: "todo", To: "doing"}, {From: "doing", To: "done"}},
+		}
+		task := models.Task{ID: 1, Title: "Tarea", Status: "todo", UserID: 1}
+		task.UseWorkflow(workflow)
+
+		task.Status = "done"
+		assert.Equal(t, []string{"status:transition"}, validationCodes(t, task.Validate()))
+		task.Status = "doing"
+		assert.NoError(t, task.Validate())
+
+		task.Status = models.TaskStatusPending
+		assert.Equal(t, []string{"status:oneof"}, validationCodes(t, task.Validate()))
+	})
+}
+
+func TestWorkflowAPI(t *testing.T) {
+	loadFakeJWTKeys(t)
+	require.NoError(t, config.LoadPasswordSettings(config.Default().Password))
+
+	repos, _ := newMemoryRepositories()
+	router := gin.New()
+	router.Use(middleware.Locale())
+	routes.SetupRoutes(router, repos)
+	user := createTestUser(t, router, "testuser_workflow")
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		return serveRequest(router, method, url, user.Token, body, "Accept-Language", "es")
+	}
+
+	t.Run("Sin flujo propio se usa el flujo por defecto", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/me/workflow", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Workflow models.WorkflowResponse `json:"workflow"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Workflow.Custom)
+		require.Len(t, response.Workflow.Statuses, 3)
+		assert.Equal(t, "Pendiente", response.Workflow.Statuses[0].Label)
+		assert.True(t, response.Workflow.Statuses[2].Done)
+	})
+
+	t.Run("Rechazar un flujo inválido", func(t *testing.T) {
+		w := request(http.MethodPut, "/api/me/workflow", `{"statuses":[{"key":"todo","done":true},{"key":"todo"}],"transitions":[{"from":"todo","to":"x"}]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.ElementsMatch(t, []string{"statuses[1].key:unique", "transitions[0].to:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+	})
+
+	var taskID float64
+	t.Run("Las tareas usan los estados del flujo propio", func(t *testing.T) {
+		w := request(http.MethodPut, "/api/me/workflow", reviewWorkflow)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = request(http.MethodPost, "/api/tasks", `{"title":"TEST flujo"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		task := decodeTask(t, w)
+		taskID = task["id"].(float64)
+		assert.Equal(t, "todo", task["status"], "el primer estado es el inicial")
+		assert.Equal(t, "Por hacer", task["status_label"])
+
+		w = request(http.MethodPost, "/api/tasks", `{"title":"TEST estado por defecto","status":"pending"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []string{"status:oneof"}, fieldCodes(decodeProblem(t, w).Errors))
+	})
+
+	t.Run("Solo se permiten las transiciones del flujo", func(t *testing.T) {
+		url := fmt.Sprintf("/api/tasks/%d", int(taskID))
+
+		w := request(http.MethodPut, url, `{"status":"done"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := decodeProblem(t, w)
+		assert.Equal(t, []string{"status:transition"}, fieldCodes(response.Errors))
+		assert.Equal(t, "El flujo de trabajo no permite pasar de todo a done", response.Errors[0].Message)
+
+		w = request(http.MethodPut, url, `{"status":"in_review"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, false, decodeTask(t, w)["is_completed"])
+
+		w = request(http.MethodPut, url, `{"status":"done"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		task := decodeTask(t, w)
+		assert.Equal(t, true, task["is_completed"], "done cuenta como completado")
+		assert.Equal(t, "green", task["status_color"])
+	})
+
+	t.Run("Los estados de la API de tareas vienen del flujo", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/task-statuses", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Statuses []struct {
+				ID    string `json:"id"`
+				Label string `json:"label"`
+				Done  bool   `json:"done"`
+			} `json:"statuses"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Statuses, 4)
+		assert.Equal(t, "in_review", response.Statuses[1].ID)
+		assert.Equal(t, "En revisión", response.Statuses[1].Label)
+		assert.True(t, response.Statuses[3].Done)
+	})
+
+	t.Run("No se pueden quitar estados con tareas", func(t *testing.T) {
+		w := request(http.MethodDelete, "/api/me/workflow", "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "status_in_use", response["code"])
+		assert.Equal(t, []interface{}{"done"}, response["statuses"])
+
+		w = request(http.MethodDelete, fmt.Sprintf("/api/tasks/%d", int(taskID)), "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = request(http.MethodDelete, "/api/me/workflow", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"custom":false`)
+	})
+}
+
+func TestWorkflowRepository(t *testing.T) {
+	setupTestDB()
+	cleanupTestData()
+	defer cleanupTestData()
+
+	router := setupRouter()
+	user := createTestUser(t, router, "testuser_workflow_db")
+	repos := repositories.NewGormRepositories(config.DB)
+	ctx := context.Background()
+
+	workflow := &models.Workflow{
+		Statuses: []models.WorkflowStatus{
+			{Key: "todo", Name: "Por hacer"},
+			{Key: "shipped", Done: true},
+		},
+		Transitions: []models.WorkflowTransition{{From: "todo", To: "shipped"}},
+	}
+	require.NoError(t, repos.Tx.WithinTransaction(ctx, func(tx repositories.Repositories) error {
+		return tx.Workflows.Replace(ctx, user.ID, workflow)
+	}))
+
+	stored, err := repos.Workflows.FindByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Custom)
+	assert.Equal(t, []string{"todo", "shipped"}, stored.Keys())
+	assert.True(t, stored.IsDone("shipped"))
+	assert.False(t, stored.CanTransition("shipped", "todo"))
+
+	// Una tarea vencida en un estado completado del flujo no cuenta como vencida
+	before, err := repos.Tasks.CountAll(ctx, time.Now())
+	require.NoError(t, err)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, config.DB.Create(&[]models.Task{
+		{Title: "TEST enviada", Status: "shipped", DueDate: &yesterday, UserID: user.ID},
+		{Title: "TEST por hacer", Status: "todo", DueDate: &yesterday, UserID: user.ID},
+	}).Error)
+	after, err := repos.Tasks.CountAll(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, before.Overdue+1, after.Overdue)
+
+	inUse, err := repos.Tasks.StatusesInUse(ctx, user.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"todo", "shipped"}, inUse)
+
+	require.NoError(t, repos.Workflows.Delete(ctx, user.ID))
+	_, err = repos.Workflows.FindByUser(ctx, user.ID)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
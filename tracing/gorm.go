@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// Clave con la que se guarda el contexto anterior al span de la sentencia
+const parentContextKey = "tracing:parent_context"
+
+// GormPlugin crea un span hijo del contexto de la petición por cada sentencia SQL.
+// El span lleva la sentencia con sus placeholders (sin los valores), la tabla y las filas afectadas
+type GormPlugin struct{}
+
+// NewGormPlugin crea el plugin para registrarlo con db.Use
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	register := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("*").Register, callbacks.Create().After("*").Register},
+		{"query", callbacks.Query().Before("*").Register, callbacks.Query().After("*").Register},
+		{"update", callbacks.Update().Before("*").Register, callbacks.Update().After("*").Register},
+		{"delete", callbacks.Delete().Before("*").Register, callbacks.Delete().After("*").Register},
+		{"row", callbacks.Row().Before("*").Register, callbacks.Row().After("*").Register},
+		{"raw", callbacks.Raw().Before("*").Register, callbacks.Raw().After("*").Register},
+	}
+	for _, r := range register {
+		if err := r.before("tracing:before_"+r.name, startStatementSpan(r.name)); err != nil {
+			return err
+		}
+		if err := r.after("tracing:after_"+r.name, endStatementSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startStatementSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, _ := Start(parent, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNameMySQL),
+		)
+		tx.InstanceSet(parentContextKey, parent)
+		tx.Statement.Context = ctx
+	}
+}
+
+func endStatementSpan(tx *gorm.DB) {
+	parent, ok := tx.InstanceGet(parentContextKey)
+	if !ok {
+		return
+	}
+	span := trace.SpanFromContext(tx.Statement.Context)
+	// Las siguientes sentencias de la misma sesión cuelgan del span original, no de este
+	tx.Statement.Context = parent.(context.Context)
+
+	query := tx.Statement.SQL.String()
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(query), " ", 2)[0])
+	if operation != "" {
+		span.SetName(strings.TrimSpace(operation + " " + tx.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(query),
+		semconv.DBOperationName(operation),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+
+	// No encontrar un registro es una respuesta válida, no un fallo de la base de datos
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
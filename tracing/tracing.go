@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go-task-manager-mvc/buildinfo"
+	"go-task-manager-mvc/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Nombre del tracer con el que se crean los spans propios de la aplicación
+const instrumentationName = "go-task-manager-mvc"
+
+// Setup instala el propagador W3C (traceparent y baggage) y, si el exportador es otlp,
+// un proveedor global que envía los spans por OTLP/HTTP. Retorna la función que envía
+// los spans pendientes y cierra el exportador
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg.Exporter != "otlp" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Los fallos al exportar no interrumpen las peticiones: solo se registran
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Warn("Error al exportar trazas", "error", err)
+	}))
+
+	// El destino, las cabeceras y el TLS se leen de OTEL_EXPORTER_OTLP_*
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el exportador OTLP: %w", err)
+	}
+	provider, err := NewProvider(ctx, cfg, sdktrace.NewBatchSpanProcessor(exporter))
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider crea un proveedor con el nombre de servicio y el muestreo de cfg que entrega
+// los spans a processor. Los tests lo usan con un exportador en memoria
+func NewProvider(ctx context.Context, cfg config.TracingConfig, processor sdktrace.SpanProcessor) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(buildinfo.Get().Version),
+		),
+		// OTEL_RESOURCE_ATTRIBUTES añade atributos como deployment.environment
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el recurso de trazas: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithSpanProcessor(processor),
+	), nil
+}
+
+// Start inicia un span hijo del span de ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End marca el span como fallido si err no es nil y lo cierra
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
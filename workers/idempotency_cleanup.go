@@ -0,0 +1,23 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-task-manager-mvc/repositories"
+)
+
+// NewIdempotencyCleanup crea el worker que elimina las claves de idempotencia expiradas
+func NewIdempotencyCleanup(keys repositories.IdempotencyRepository, interval time.Duration) Worker {
+	return Every("idempotency-cleanup", interval, func(ctx context.Context) error {
+		deleted, err := keys.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			slog.Info("Claves de idempotencia expiradas eliminadas", "deleted", deleted)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,13 @@
+package workers
+
+import (
+	"time"
+
+	"go-task-manager-mvc/config"
+)
+
+// NewReplicaHealthCheck crea el worker que comprueba periódicamente las réplicas de lectura,
+// para dejar de usar las caídas y volver a usar las recuperadas
+func NewReplicaHealthCheck(replicas *config.ReplicaSet, interval time.Duration) Worker {
+	return Every("replica-health", interval, replicas.CheckHealth)
+}
@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-task-manager-mvc/repositories"
+)
+
+// NewSessionCleanup crea el worker que elimina las sesiones expiradas o revocadas
+// hace más de retention, para que la tabla sessions no crezca sin límite
+func NewSessionCleanup(sessions repositories.SessionRepository, interval, retention time.Duration) Worker {
+	return Every("session-cleanup", interval, func(ctx context.Context) error {
+		deleted, err := sessions.DeleteInactive(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			slog.Info("Sesiones inactivas eliminadas", "deleted", deleted)
+		}
+		return nil
+	})
+}
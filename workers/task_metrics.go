@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"go-task-manager-mvc/metrics"
+	"go-task-manager-mvc/models"
+	"go-task-manager-mvc/repositories"
+)
+
+// NewTaskMetrics crea el worker que actualiza las métricas de tareas por estado y vencidas.
+// Se calculan cada interval en lugar de en cada scrape para no cargar la base de datos
+func NewTaskMetrics(tasks repositories.TaskRepository, interval time.Duration) Worker {
+	return Every("task-metrics", interval, func(ctx context.Context) error {
+		counts, err := tasks.CountAll(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		statuses := append(models.GetValidTasksStatuesList(), repositories.OtherTaskStatus)
+		metrics.SetTaskCounts(statuses, counts.ByStatus, counts.Overdue)
+		return nil
+	})
+}
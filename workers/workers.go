@@ -0,0 +1,138 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Worker es una tarea de fondo que corre hasta que se cancela su contexto
+type Worker interface {
+	Name() string
+	Run(ctx context.Context)
+}
+
+// Manager arranca los workers y espera a que terminen al apagar la aplicación
+type Manager struct {
+	workers []Worker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewManager(workers ...Worker) *Manager {
+	return &Manager{workers: workers, running: make(map[string]bool)}
+}
+
+// Start lanza cada worker en su propia goroutine
+func (m *Manager) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	for _, worker := range m.workers {
+		m.setRunning(worker.Name(), true)
+		m.wg.Add(1)
+		go func(w Worker) {
+			defer m.wg.Done()
+			defer m.setRunning(w.Name(), false)
+			slog.Info("Worker iniciado", "worker", w.Name())
+			w.Run(ctx)
+			slog.Info("Worker detenido", "worker", w.Name())
+		}(worker)
+	}
+}
+
+// Check retorna un error si los workers no se iniciaron o alguno se detuvo
+func (m *Manager) Check(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel == nil && len(m.workers) > 0 {
+		return errors.New("los workers no se han iniciado")
+	}
+
+	var stopped []string
+	for name, running := range m.running {
+		if !running {
+			stopped = append(stopped, name)
+		}
+	}
+	if len(stopped) > 0 {
+		sort.Strings(stopped)
+		return fmt.Errorf("workers detenidos: %s", strings.Join(stopped, ", "))
+	}
+	return nil
+}
+
+func (m *Manager) setRunning(name string, running bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running[name] = running
+}
+
+// Stop cancela los workers y espera a que terminen o a que venza ctx
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// periodicWorker ejecuta una función cada cierto intervalo
+type periodicWorker struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// Every crea un worker que ejecuta fn al arrancar y después cada interval.
+// Los errores se registran y no detienen el worker
+func Every(name string, interval time.Duration, fn func(ctx context.Context) error) Worker {
+	return &periodicWorker{name: name, interval: interval, fn: fn}
+}
+
+func (w *periodicWorker) Name() string {
+	return w.name
+}
+
+func (w *periodicWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.fn(ctx); err != nil {
+			slog.Error("Error en worker", "worker", w.name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}